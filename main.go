@@ -2,13 +2,82 @@ package main
 
 import (
 	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/cache"
 	"ChatRoomAPI/src/controller"
+	"ChatRoomAPI/src/importer"
+	"ChatRoomAPI/src/migration"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"ChatRoomAPI/src/worker"
+	"context"
 	"fmt"
+	"log"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migration.Run(src.GlobalConfig.DB); err != nil {
+			log.Fatalf("migrate failed: %v", err)
+		}
+		fmt.Println("migrate done")
+		return
+	}
+
+	// import <slack|discord> <archive> <owner user id> runs an import in the foreground, for
+	// archives too big to upload. Running it again resumes where it stopped.
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if len(os.Args) != 5 {
+			log.Fatalf("usage: %s import <slack|discord> <archive> <owner user id>", os.Args[0])
+		}
+		data, err := os.ReadFile(os.Args[3])
+		if err != nil {
+			log.Fatalf("import failed: %v", err)
+		}
+		ownerUserID, err := strconv.ParseUint(os.Args[4], 10, 64)
+		if err != nil {
+			log.Fatalf("import failed: invalid owner user id %q", os.Args[4])
+		}
+		result, err := importer.New().Run(context.Background(), os.Args[2], data, ownerUserID)
+		if err != nil {
+			log.Fatalf("import failed: %v", err)
+		}
+		fmt.Printf("import done: %d rooms, %d messages\n", result.RoomCount, result.MessageCount)
+		return
+	}
+
+	// ops-admin <grant|revoke> <user id> hands out the platform admin role, which is deliberately
+	// not possible over the API.
+	if len(os.Args) > 1 && os.Args[1] == "ops-admin" {
+		if len(os.Args) != 4 || (os.Args[2] != "grant" && os.Args[2] != "revoke") {
+			log.Fatalf("usage: %s ops-admin <grant|revoke> <user id>", os.Args[0])
+		}
+		userID, err := strconv.ParseUint(os.Args[3], 10, 64)
+		if err != nil {
+			log.Fatalf("ops-admin failed: invalid user id %q", os.Args[3])
+		}
+		role := ""
+		if os.Args[2] == "grant" {
+			role = model.PlatformRoleAdmin
+		}
+		ok, err := repository.GetAccountRepository().SetPlatformRole(context.Background(), userID, role)
+		if err != nil {
+			log.Fatalf("ops-admin failed: %v", err)
+		} else if !ok {
+			log.Fatalf("ops-admin failed: user %d does not exist", userID)
+		}
+		if err := cache.GetAccountStatusCache().ClearAccountStatus(context.Background(), userID); err != nil {
+			log.Printf("ops-admin: clearing the cached account status failed, it expires on its own: %v", err)
+		}
+		fmt.Printf("ops-admin %s done for user %d\n", os.Args[2], userID)
+		return
+	}
+
+	worker.Start(context.Background())
+
 	gin.SetMode(gin.ReleaseMode)
 	root := gin.New()
 	root.SetTrustedProxies([]string{"192.168.1.1", "127.0.0.1"})
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ChatRoomAPI/src"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AccountStatus is what the login filter needs to know about a user on every request.
+type AccountStatus struct {
+	PlatformRole string
+	Suspended    bool
+}
+
+type AccountStatusCache interface {
+	StoreAccountStatus(ctx context.Context, userId uint64, status AccountStatus) error
+	GetAccountStatus(ctx context.Context, userId uint64) (*AccountStatus, bool, error)
+	ClearAccountStatus(ctx context.Context, userId uint64) error
+}
+
+type accountStatusCacheImpl struct {
+	redisClient    *redis.Client
+	keyExpiredTime time.Duration
+	tracer         trace.Tracer
+}
+
+func (a *accountStatusCacheImpl) getAccountStatusKey(userId uint64) string {
+	return fmt.Sprintf("account::status::user:%d", userId)
+}
+
+func (a *accountStatusCacheImpl) StoreAccountStatus(ctx context.Context, userId uint64, status AccountStatus) error {
+	ctx, span := a.tracer.Start(ctx, "StoreAccountStatus")
+	defer span.End()
+
+	key := a.getAccountStatusKey(userId)
+	pipe := a.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "role", status.PlatformRole, "suspended", status.Suspended)
+	pipe.Expire(ctx, key, a.keyExpiredTime)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("store account status failed: %w", err)
+	}
+	return nil
+}
+
+func (a *accountStatusCacheImpl) GetAccountStatus(ctx context.Context, userId uint64) (*AccountStatus, bool, error) {
+	fields, err := a.redisClient.HGetAll(ctx, a.getAccountStatusKey(userId)).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis HGetAll failed: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+	// go-redis writes a bool as "1" or "0"
+	return &AccountStatus{PlatformRole: fields["role"], Suspended: fields["suspended"] == "1"}, true, nil
+}
+
+func (a *accountStatusCacheImpl) ClearAccountStatus(ctx context.Context, userId uint64) error {
+	if err := a.redisClient.Del(ctx, a.getAccountStatusKey(userId)).Err(); err != nil {
+		return fmt.Errorf("redis DEL failed: %w", err)
+	}
+	return nil
+}
+
+var accountStatus AccountStatusCache
+
+func init() {
+	accountStatus = &accountStatusCacheImpl{
+		keyExpiredTime: 5 * time.Minute,
+		redisClient:    src.GlobalConfig.Redis,
+		tracer:         otel.Tracer("accountStatusCache"),
+	}
+}
+
+func GetAccountStatusCache() AccountStatusCache {
+	return accountStatus
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ChatRoomAPI/src"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type BlockCache interface {
+	StoreBlockedUserIDs(ctx context.Context, userId uint64, blockedUserIds []uint64) error
+	GetBlockedUserIDs(ctx context.Context, userId uint64) (map[uint64]struct{}, bool, error)
+	ClearBlockCacheByUser(ctx context.Context, userId uint64) error
+}
+
+type blockCacheImpl struct {
+	redisClient    *redis.Client
+	keyExpiredTime time.Duration
+	tracer         trace.Tracer
+}
+
+// user ids start from 1, so 0 is kept in every set to tell "blocks nobody" apart from a cache miss
+const blockPlaceholderMember = 0
+
+func (b *blockCacheImpl) getUserBlockKey(userId uint64) string {
+	return fmt.Sprintf("block::user:%d", userId)
+}
+
+func (b *blockCacheImpl) StoreBlockedUserIDs(ctx context.Context, userId uint64, blockedUserIds []uint64) error {
+	ctx, span := b.tracer.Start(ctx, "StoreBlockedUserIDs")
+	defer span.End()
+
+	key := b.getUserBlockKey(userId)
+	members := make([]interface{}, 0, len(blockedUserIds)+1)
+	members = append(members, blockPlaceholderMember)
+	for _, id := range blockedUserIds {
+		members = append(members, id)
+	}
+
+	pipe := b.redisClient.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.SAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, b.keyExpiredTime)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("store blocked user ids failed: %w", err)
+	}
+	return nil
+}
+
+func (b *blockCacheImpl) GetBlockedUserIDs(ctx context.Context, userId uint64) (map[uint64]struct{}, bool, error) {
+	key := b.getUserBlockKey(userId)
+	members, err := b.redisClient.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("redis SMembers failed: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, false, nil
+	}
+
+	blocked := make(map[uint64]struct{}, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid blocked user id: %s", member)
+		}
+		if id == blockPlaceholderMember {
+			continue
+		}
+		blocked[id] = struct{}{}
+	}
+	b.redisClient.Expire(ctx, key, b.keyExpiredTime)
+	return blocked, true, nil
+}
+
+func (b *blockCacheImpl) ClearBlockCacheByUser(ctx context.Context, userId uint64) error {
+	if err := b.redisClient.Del(ctx, b.getUserBlockKey(userId)).Err(); err != nil {
+		return fmt.Errorf("redis DEL failed: %w", err)
+	}
+	return nil
+}
+
+var block BlockCache
+
+func init() {
+	block = &blockCacheImpl{
+		keyExpiredTime: 60 * time.Minute,
+		redisClient:    src.GlobalConfig.Redis,
+		tracer:         otel.Tracer("blockCache"),
+	}
+}
+
+func GetBlockCache() BlockCache {
+	return block
+}
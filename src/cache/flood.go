@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"ChatRoomAPI/src"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type FloodCache interface {
+	// Repeat counts one more sending of content by userId in roomId and reports whether it went over
+	// the number of identical messages allowed within the window.
+	Repeat(ctx context.Context, roomId uint64, userId uint64, content string) (flooding bool, err error)
+}
+
+// floodCacheImpl allows maxRepeat identical messages per user and room within window, taken from
+// filter.flood in config. A zero maxRepeat disables the check.
+type floodCacheImpl struct {
+	redisClient *redis.Client
+	maxRepeat   int64
+	window      time.Duration
+	tracer      trace.Tracer
+}
+
+// floodScript counts a message and starts the window with the first one.
+// KEYS[1] flood key; ARGV[1] window ms.
+var floodScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+func (f *floodCacheImpl) getFloodKey(roomId uint64, userId uint64, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("message::flood::room:%d::user:%d::%s", roomId, userId, hex.EncodeToString(sum[:8]))
+}
+
+func (f *floodCacheImpl) Repeat(ctx context.Context, roomId uint64, userId uint64, content string) (bool, error) {
+	if f.maxRepeat <= 0 {
+		return false, nil
+	}
+	ctx, span := f.tracer.Start(ctx, "Repeat")
+	defer span.End()
+
+	keys := []string{f.getFloodKey(roomId, userId, content)}
+	count, err := floodScript.Run(ctx, f.redisClient, keys, f.window.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("redis flood count failed: %w", err)
+	}
+	return count > f.maxRepeat, nil
+}
+
+var flood FloodCache
+
+func init() {
+	config := src.GlobalConfig.YamlConfig.Filter.Flood
+	flood = &floodCacheImpl{
+		redisClient: src.GlobalConfig.Redis,
+		maxRepeat:   int64(config.MaxRepeat),
+		window:      time.Duration(config.Second) * time.Second,
+		tracer:      otel.Tracer("floodCache"),
+	}
+}
+
+func GetFloodCache() FloodCache {
+	return flood
+}
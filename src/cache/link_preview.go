@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ChatRoomAPI/src"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LinkPreviewCacheInfo is the preview of a url shared by every message linking to it. An empty
+// one is cached as well, so a page without metadata or that failed to load is not fetched again
+// for every message.
+type LinkPreviewCacheInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	SiteName    string `json:"site_name"`
+}
+
+func (l *LinkPreviewCacheInfo) Empty() bool {
+	return l.Title == "" && l.Description == "" && l.ImageURL == ""
+}
+
+type LinkPreviewCache interface {
+	StorePreview(ctx context.Context, url string, preview *LinkPreviewCacheInfo) error
+	GetPreview(ctx context.Context, url string) (*LinkPreviewCacheInfo, bool, error)
+}
+
+type linkPreviewCacheImpl struct {
+	redisClient      *redis.Client
+	keyExpiredTime   time.Duration
+	emptyExpiredTime time.Duration
+	tracer           trace.Tracer
+}
+
+// getPreviewKey hashes the url, urls can be long and hold anything.
+func (l *linkPreviewCacheImpl) getPreviewKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return fmt.Sprintf("unfurl::url:%s", hex.EncodeToString(sum[:]))
+}
+
+func (l *linkPreviewCacheImpl) StorePreview(ctx context.Context, url string, preview *LinkPreviewCacheInfo) error {
+	ctx, span := l.tracer.Start(ctx, "StorePreview")
+	defer span.End()
+
+	data, err := json.Marshal(preview)
+	if err != nil {
+		return fmt.Errorf("encode link preview failed: %w", err)
+	}
+	expire := l.keyExpiredTime
+	if preview.Empty() {
+		expire = l.emptyExpiredTime
+	}
+	if err := l.redisClient.Set(ctx, l.getPreviewKey(url), data, expire).Err(); err != nil {
+		return fmt.Errorf("redis SET failed: %w", err)
+	}
+	return nil
+}
+
+func (l *linkPreviewCacheImpl) GetPreview(ctx context.Context, url string) (*LinkPreviewCacheInfo, bool, error) {
+	ctx, span := l.tracer.Start(ctx, "GetPreview")
+	defer span.End()
+
+	data, err := l.redisClient.Get(ctx, l.getPreviewKey(url)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("redis GET failed: %w", err)
+	}
+	preview := LinkPreviewCacheInfo{}
+	if err := json.Unmarshal(data, &preview); err != nil {
+		return nil, false, fmt.Errorf("invalid link preview: %w", err)
+	}
+	return &preview, true, nil
+}
+
+var linkPreview LinkPreviewCache
+
+func init() {
+	linkPreview = &linkPreviewCacheImpl{
+		redisClient:      src.GlobalConfig.Redis,
+		keyExpiredTime:   24 * time.Hour,
+		emptyExpiredTime: time.Hour,
+		tracer:           otel.Tracer("linkPreviewCache"),
+	}
+}
+
+func GetLinkPreviewCache() LinkPreviewCache {
+	return linkPreview
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ChatRoomAPI/src"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	MessageLimitAllowed = iota
+	MessageLimitSlowMode
+	MessageLimitBurst
+)
+
+type MessageLimitCache interface {
+	// Acquire checks slow mode and the burst window of userId in roomId and, only when both pass,
+	// records the message. A zero slowMode disables slow mode.
+	Acquire(ctx context.Context, roomId uint64, userId uint64, slowMode time.Duration) (result int, retryAfter time.Duration, err error)
+}
+
+// messageLimitCacheImpl allows burst messages per user and room within window, taken from
+// server.rate_limit.message in config. A zero burst disables the limit.
+type messageLimitCacheImpl struct {
+	redisClient *redis.Client
+	burst       int
+	window      time.Duration
+	tracer      trace.Tracer
+}
+
+// acquireScript runs check and update in one step, so two concurrent sends can not both slip
+// through the last free slot.
+// KEYS[1] slow mode key, KEYS[2] burst key; ARGV[1] slow mode ms, ARGV[2] burst size, ARGV[3] window ms.
+var acquireScript = redis.NewScript(`
+local slowMode = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+
+if slowMode > 0 then
+	local ttl = redis.call('PTTL', KEYS[1])
+	if ttl > 0 then
+		return {1, ttl}
+	end
+end
+
+if burst > 0 then
+	local count = tonumber(redis.call('GET', KEYS[2]) or '0')
+	if count >= burst then
+		return {2, redis.call('PTTL', KEYS[2])}
+	end
+end
+
+if slowMode > 0 then
+	redis.call('SET', KEYS[1], 1, 'PX', slowMode)
+end
+if burst > 0 then
+	if redis.call('INCR', KEYS[2]) == 1 then
+		redis.call('PEXPIRE', KEYS[2], window)
+	end
+end
+return {0, 0}
+`)
+
+func (m *messageLimitCacheImpl) getSlowModeKey(roomId uint64, userId uint64) string {
+	return fmt.Sprintf("message::slowmode::room:%d::user:%d", roomId, userId)
+}
+
+func (m *messageLimitCacheImpl) getBurstKey(roomId uint64, userId uint64) string {
+	return fmt.Sprintf("message::burst::room:%d::user:%d", roomId, userId)
+}
+
+func (m *messageLimitCacheImpl) Acquire(ctx context.Context, roomId uint64, userId uint64, slowMode time.Duration) (int, time.Duration, error) {
+	ctx, span := m.tracer.Start(ctx, "Acquire")
+	defer span.End()
+
+	keys := []string{m.getSlowModeKey(roomId, userId), m.getBurstKey(roomId, userId)}
+	values, err := acquireScript.Run(ctx, m.redisClient, keys, slowMode.Milliseconds(), m.burst, m.window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return MessageLimitAllowed, 0, fmt.Errorf("redis acquire message limit failed: %w", err)
+	}
+	return int(values[0]), time.Duration(values[1]) * time.Millisecond, nil
+}
+
+var messageLimit MessageLimitCache
+
+func init() {
+	limit := src.GlobalConfig.YamlConfig.Server.RateLimitConfig.Message
+	messageLimit = &messageLimitCacheImpl{
+		redisClient: src.GlobalConfig.Redis,
+		burst:       limit.MaxRequest,
+		window:      time.Duration(limit.Second) * time.Second,
+		tracer:      otel.Tracer("messageLimitCache"),
+	}
+}
+
+func GetMessageLimitCache() MessageLimitCache {
+	return messageLimit
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StripStickers blanks the sticker::<set id>::<sticker id> tokens of content that owns rejects and
+// keeps the rest of the text as it is.
+func StripStickers(content string, owns func(stickerSetID uint64, stickerID uint64) bool) string {
+	chunks := strings.Split(content, " ")
+	for i, chunk := range chunks {
+		subchunks := strings.Split(chunk, "::")
+		if len(subchunks) != 3 || subchunks[0] != "sticker" {
+			continue
+		}
+
+		stickerSetID, err := strconv.ParseUint(subchunks[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		stickerID, err := strconv.ParseUint(subchunks[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if !owns(stickerSetID, stickerID) {
+			chunks[i] = ""
+		}
+	}
+	return strings.Join(chunks, " ")
+}
@@ -0,0 +1,37 @@
+package common
+
+import "testing"
+
+// owned mimics the sticker set cache of one user, a set the user did not buy is absent.
+func owned(sets map[uint64][]uint64) func(uint64, uint64) bool {
+	return func(stickerSetID uint64, stickerID uint64) bool {
+		for _, id := range sets[stickerSetID] {
+			if id == stickerID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestStripStickers(t *testing.T) {
+	owns := owned(map[uint64][]uint64{1: {10, 11}})
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"owned sticker", "hi sticker::1::10", "hi sticker::1::10"},
+		{"unowned sticker of an owned set", "hi sticker::1::12", "hi "},
+		{"sticker of an unowned set", "sticker::999999::1", ""},
+		{"malformed ids", "sticker::x::1 sticker::1::y", "sticker::x::1 sticker::1::y"},
+		{"not a sticker", "a::b::c plain text", "a::b::c plain text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripStickers(tt.content, owns); got != tt.want {
+				t.Errorf("StripStickers(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
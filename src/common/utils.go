@@ -2,6 +2,8 @@ package common
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -43,3 +45,12 @@ func GetUUID(c context.Context) string {
 	uuid, _ := val.(string)
 	return uuid
 }
+
+// RandomToken returns a url safe random string built from byteSize bytes of crypto/rand output.
+func RandomToken(byteSize int) (string, error) {
+	buf := make([]byte, byteSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
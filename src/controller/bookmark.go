@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func bookmarkRouter(g *gin.RouterGroup) {
+	group := g.Group("/bookmark")
+	group.Use(GetLoginFilter())
+	group.PUT("/", bookmark.SaveBookmark)
+	group.PATCH("/", bookmark.UpdateBookmark)
+	group.DELETE("/", bookmark.DeleteBookmark)
+	group.GET("/", bookmark.FetchBookmarks)
+	group.GET("/folders", bookmark.FetchBookmarkFolders)
+}
+
+type BookmarkController interface {
+	SaveBookmark(c *gin.Context)
+	UpdateBookmark(c *gin.Context)
+	DeleteBookmark(c *gin.Context)
+	FetchBookmarks(c *gin.Context)
+	FetchBookmarkFolders(c *gin.Context)
+}
+
+type bookmarkControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var bookmark BookmarkController
+
+func init() {
+	bookmark = &bookmarkControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+func (b *bookmarkControllerImpl) SaveBookmark(c *gin.Context) {
+	var req dto.SaveBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := b.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetBookmarkService().SaveBookmark(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (b *bookmarkControllerImpl) UpdateBookmark(c *gin.Context) {
+	var req dto.UpdateBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := b.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetBookmarkService().UpdateBookmark(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (b *bookmarkControllerImpl) DeleteBookmark(c *gin.Context) {
+	var req dto.DeleteBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := b.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetBookmarkService().DeleteBookmark(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (b *bookmarkControllerImpl) FetchBookmarks(c *gin.Context) {
+	var req dto.FetchBookmarksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := b.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetBookmarkService().FetchBookmarks(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (b *bookmarkControllerImpl) FetchBookmarkFolders(c *gin.Context) {
+	_, userId, _ := GetSessionValue(c)
+	req := dto.FetchBookmarkFoldersRequest{UserID: userId}
+
+	res, serviceErr := service.GetBookmarkService().FetchBookmarkFolders(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
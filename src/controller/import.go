@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportArchiveSize bounds the uploaded archive, bigger exports go through the import command.
+const maxImportArchiveSize = 64 << 20
+
+func importRouter(g *gin.RouterGroup) {
+	group := g.Group("/import")
+	group.Use(GetLoginFilter())
+	group.PUT("/", importController.CreateImport)
+	group.GET("/", importController.FetchImport)
+}
+
+type ImportController interface {
+	CreateImport(c *gin.Context)
+	FetchImport(c *gin.Context)
+}
+
+type importControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var importController ImportController
+
+func init() {
+	importController = &importControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+// CreateImport takes a multipart form with the source and the archive file.
+func (i *importControllerImpl) CreateImport(c *gin.Context) {
+	var req dto.CreateImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		serviceErr := i.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	header, err := c.FormFile("archive")
+	if err != nil {
+		serviceErr := i.errWarpper.NewParseFormatFailedServiceError(err, "archive file is missing")
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	} else if header.Size > maxImportArchiveSize {
+		serviceErr := i.errWarpper.NewParseFormatFailedServiceError(nil, fmt.Sprintf("archive is larger than %d bytes", maxImportArchiveSize))
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		serviceErr := i.errWarpper.NewParseFormatFailedServiceError(err, "archive file can not be read")
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	defer file.Close()
+	req.Archive, err = io.ReadAll(file)
+	if err != nil {
+		serviceErr := i.errWarpper.NewParseFormatFailedServiceError(err, "archive file can not be read")
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := service.GetImportService().CreateImport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (i *importControllerImpl) FetchImport(c *gin.Context) {
+	req := dto.FetchImportRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := i.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := service.GetImportService().FetchImport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
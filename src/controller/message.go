@@ -14,6 +14,13 @@ import (
 type MessageGroupController interface {
 	AddMessage(c *gin.Context)
 	FetchMessages(c *gin.Context)
+	DeleteMessage(c *gin.Context)
+	PinMessage(c *gin.Context)
+	UnpinMessage(c *gin.Context)
+	FetchPinnedMessages(c *gin.Context)
+	CreatePoll(c *gin.Context)
+	VotePoll(c *gin.Context)
+	ForwardMessage(c *gin.Context)
 }
 
 type messageGroupControllerImpl struct {
@@ -68,9 +75,142 @@ func (m *messageGroupControllerImpl) FetchMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"result": res})
 }
 
+func (m *messageGroupControllerImpl) DeleteMessage(c *gin.Context) {
+	var req dto.DeleteMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetMessageService().DeleteMessage(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (m *messageGroupControllerImpl) PinMessage(c *gin.Context) {
+	var req dto.PinMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetMessageService().PinMessage(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (m *messageGroupControllerImpl) UnpinMessage(c *gin.Context) {
+	var req dto.UnpinMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetMessageService().UnpinMessage(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (m *messageGroupControllerImpl) FetchPinnedMessages(c *gin.Context) {
+	req := dto.FetchPinnedMessagesRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := m.errWarper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetMessageService().FetchPinnedMessages(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (m *messageGroupControllerImpl) CreatePoll(c *gin.Context) {
+	var req dto.CreatePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetMessageService().CreatePoll(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (m *messageGroupControllerImpl) VotePoll(c *gin.Context) {
+	var req dto.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetMessageService().VotePoll(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (m *messageGroupControllerImpl) ForwardMessage(c *gin.Context) {
+	var req dto.ForwardMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := m.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetMessageService().ForwardMessage(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
 func messageGroupRouter(g *gin.RouterGroup) {
 	group := g.Group("/message")
 	group.Use(GetLoginFilter())
 	group.POST("/", message.AddMessage)
 	group.GET("/", message.FetchMessages)
+	group.DELETE("/", message.DeleteMessage)
+	group.PUT("/pin", message.PinMessage)
+	group.DELETE("/pin", message.UnpinMessage)
+	group.GET("/pins", message.FetchPinnedMessages)
+	group.POST("/poll", message.CreatePoll)
+	group.PUT("/poll/vote", message.VotePoll)
+	group.POST("/forward", message.ForwardMessage)
 }
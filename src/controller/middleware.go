@@ -3,7 +3,10 @@ package controller
 import (
 	"ChatRoomAPI/src"
 	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dtoError"
 	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/service"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -51,21 +54,50 @@ var loginFilter func(*gin.Context)
 var log = logger.NewLogger()
 var once sync.Once
 
+// platformRoleKey is where the login filter leaves the platform role for the filters after it.
+const platformRoleKey = "platform_role"
+
+// GetLoginFilter lets through logged in users that are not suspended. The suspension is checked on
+// every request, so it also ends the sessions a user already has.
 func GetLoginFilter() func(*gin.Context) {
 	once.Do(func() {
 		loginFilter = func(c *gin.Context) {
-			ok, _, _ := GetSessionValue(c)
+			ok, userId, _ := GetSessionValue(c)
 			if !ok {
 				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not logged in"})
 				c.Abort()
 				return
 			}
+			status, serviceErr := service.GetOpsService().AccountStatus(c, userId)
+			if serviceErr == nil && status.Suspended {
+				serviceErr = dtoError.GetServiceErrorWarpper().NewUserSuspendedError(userId)
+			}
+			if serviceErr != nil {
+				c.JSON(serviceErr.ToJsonResponse())
+				c.Abort()
+				return
+			}
+			c.Set(platformRoleKey, status.PlatformRole)
 			c.Next()
 		}
 	})
 	return loginFilter
 }
 
+// GetPlatformAdminFilter has to run after the login filter.
+func GetPlatformAdminFilter() func(*gin.Context) {
+	return func(c *gin.Context) {
+		if c.GetString(platformRoleKey) != model.PlatformRoleAdmin {
+			_, userId, _ := GetSessionValue(c)
+			serviceErr := dtoError.GetServiceErrorWarpper().NewNotPlatformAdminError(userId)
+			c.JSON(serviceErr.ToJsonResponse())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func SetSessionValue(c *gin.Context, ID uint64, username string) (string, error) {
 	session := sessions.Default(c)
 	session.Set("id", strconv.FormatUint(ID, 10))
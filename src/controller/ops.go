@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// opsRouter is the platform operators' API, only users with the admin platform role get in.
+func opsRouter(g *gin.RouterGroup) {
+	group := g.Group("/ops")
+	group.Use(GetLoginFilter(), GetPlatformAdminFilter())
+	group.GET("/users", ops.SearchUsers)
+	group.PATCH("/user/suspend", ops.SuspendUser)
+	group.PATCH("/user/unsuspend", ops.UnsuspendUser)
+	group.DELETE("/room", ops.PurgeRoom)
+	group.PATCH("/room/legal_hold", ops.SetLegalHold)
+	group.PATCH("/wallet", ops.AdjustWallet)
+	group.GET("/audit_logs", ops.FetchAuditLogs)
+	group.GET("/reports", ops.FetchEscalatedReports)
+	group.PATCH("/report/claim", ops.ClaimEscalatedReport)
+	group.PATCH("/report/resolve", ops.ResolveEscalatedReport)
+	group.PATCH("/report/dismiss", ops.DismissEscalatedReport)
+}
+
+type OpsController interface {
+	SearchUsers(c *gin.Context)
+	SuspendUser(c *gin.Context)
+	UnsuspendUser(c *gin.Context)
+	PurgeRoom(c *gin.Context)
+	SetLegalHold(c *gin.Context)
+	AdjustWallet(c *gin.Context)
+	FetchAuditLogs(c *gin.Context)
+	FetchEscalatedReports(c *gin.Context)
+	ClaimEscalatedReport(c *gin.Context)
+	ResolveEscalatedReport(c *gin.Context)
+	DismissEscalatedReport(c *gin.Context)
+}
+
+type opsControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var ops OpsController
+
+func init() {
+	ops = &opsControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+func (o *opsControllerImpl) SearchUsers(c *gin.Context) {
+	var req dto.SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	res, serviceErr := service.GetOpsService().SearchUsers(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (o *opsControllerImpl) SuspendUser(c *gin.Context) {
+	var req dto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetOpsService().SuspendUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) UnsuspendUser(c *gin.Context) {
+	var req dto.UnsuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetOpsService().UnsuspendUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) PurgeRoom(c *gin.Context) {
+	var req dto.PurgeRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetOpsService().PurgeRoom(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) SetLegalHold(c *gin.Context) {
+	var req dto.SetLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetOpsService().SetLegalHold(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) AdjustWallet(c *gin.Context) {
+	var req dto.AdjustWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	res, serviceErr := service.GetOpsService().AdjustWallet(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (o *opsControllerImpl) FetchAuditLogs(c *gin.Context) {
+	var req dto.FetchAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	res, serviceErr := service.GetOpsService().FetchAuditLogs(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (o *opsControllerImpl) FetchEscalatedReports(c *gin.Context) {
+	var req dto.FetchEscalatedReportsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	res, serviceErr := service.GetOpsService().FetchEscalatedReports(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (o *opsControllerImpl) ClaimEscalatedReport(c *gin.Context) {
+	var req dto.ClaimReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().ClaimEscalatedReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) ResolveEscalatedReport(c *gin.Context) {
+	var req dto.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().ResolveEscalatedReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (o *opsControllerImpl) DismissEscalatedReport(c *gin.Context) {
+	var req dto.DismissReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := o.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().DismissEscalatedReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
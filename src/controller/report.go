@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func reportRouter(g *gin.RouterGroup) {
+	group := g.Group("/report")
+	group.Use(GetLoginFilter())
+	group.PUT("/", report.FileReport)
+	group.GET("/queue", report.FetchReports)
+	group.PATCH("/claim", report.ClaimReport)
+	group.PATCH("/resolve", report.ResolveReport)
+	group.PATCH("/dismiss", report.DismissReport)
+}
+
+type ReportController interface {
+	FileReport(c *gin.Context)
+	FetchReports(c *gin.Context)
+	ClaimReport(c *gin.Context)
+	ResolveReport(c *gin.Context)
+	DismissReport(c *gin.Context)
+}
+
+type reportControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var report ReportController
+
+func init() {
+	report = &reportControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+func (r *reportControllerImpl) FileReport(c *gin.Context) {
+	var req dto.FileReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetReportService().FileReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *reportControllerImpl) FetchReports(c *gin.Context) {
+	var req dto.FetchReportsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	res, serviceErr := service.GetReportService().FetchReports(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *reportControllerImpl) ClaimReport(c *gin.Context) {
+	var req dto.ClaimReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().ClaimReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *reportControllerImpl) ResolveReport(c *gin.Context) {
+	var req dto.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().ResolveReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *reportControllerImpl) DismissReport(c *gin.Context) {
+	var req dto.DismissReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+
+	_, serviceErr := service.GetReportService().DismissReport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
@@ -15,9 +15,14 @@ func roomGroupRouter(g *gin.RouterGroup) {
 	group.Use(GetLoginFilter())
 
 	group.PUT("/", room.CreateRoom)
+	group.PUT("/direct", room.OpenDirectRoom)
 	group.GET("/", room.GetAvailbleRooms)
 	group.GET("/info", room.GetRoomInfo)
+	group.GET("/directory", room.SearchRoomDirectory)
 	group.DELETE("/", room.DeleteRoom)
+	group.PATCH("/archive", room.ArchiveRoom)
+	group.GET("/deleted", room.FetchDeletedRooms)
+	group.PATCH("/restore", room.RestoreRoom)
 
 	roomAdminGroupRouter(group)
 	roomUserGroupRouter(group)
@@ -25,9 +30,14 @@ func roomGroupRouter(g *gin.RouterGroup) {
 
 type RoomController interface {
 	CreateRoom(c *gin.Context)
+	OpenDirectRoom(c *gin.Context)
 	GetAvailbleRooms(c *gin.Context)
 	GetRoomInfo(c *gin.Context)
+	SearchRoomDirectory(c *gin.Context)
 	DeleteRoom(c *gin.Context)
+	ArchiveRoom(c *gin.Context)
+	FetchDeletedRooms(c *gin.Context)
+	RestoreRoom(c *gin.Context)
 }
 
 type roomControllerImpl struct {
@@ -62,6 +72,24 @@ func (r *roomControllerImpl) CreateRoom(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"result": res})
 }
 
+func (r *roomControllerImpl) OpenDirectRoom(c *gin.Context) {
+	var req dto.OpenDirectRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := r.roomService.OpenDirectRoom(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
 func (r *roomControllerImpl) GetAvailbleRooms(c *gin.Context) {
 	_, userId, _ := GetSessionValue(c)
 	req := dto.GetAvailbleRoomsRequest{UserID: userId}
@@ -111,3 +139,66 @@ func (r *roomControllerImpl) DeleteRoom(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, gin.H{})
 }
+
+func (r *roomControllerImpl) SearchRoomDirectory(c *gin.Context) {
+	req := dto.SearchRoomDirectoryRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	res, serviceErr := r.roomService.SearchRoomDirectory(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomControllerImpl) ArchiveRoom(c *gin.Context) {
+	var req dto.ArchiveRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := r.roomService.ArchiveRoom(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomControllerImpl) FetchDeletedRooms(c *gin.Context) {
+	_, userId, _ := GetSessionValue(c)
+	req := dto.FetchDeletedRoomsRequest{AdminUserID: userId}
+	res, serviceErr := r.roomService.FetchDeletedRooms(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomControllerImpl) RestoreRoom(c *gin.Context) {
+	var req dto.RestoreRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := r.roomService.RestoreRoom(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
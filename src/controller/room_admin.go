@@ -19,6 +19,20 @@ func roomAdminGroupRouter(g *gin.RouterGroup) {
 	group.PUT("/application_confrim", roomAdmin.ConfrimApplication)
 	group.GET("/applications", roomAdmin.FetchApplications)
 	group.DELETE("/user", roomAdmin.DeleteUser)
+	group.PATCH("/role", roomAdmin.AssignRole)
+	group.PUT("/invite_link", roomAdmin.CreateInviteLink)
+	group.GET("/invite_links", roomAdmin.FetchInviteLinks)
+	group.DELETE("/invite_link", roomAdmin.RevokeInviteLink)
+	group.GET("/invite_link/uses", roomAdmin.FetchInviteLinkUses)
+	group.PATCH("/join_policy", roomAdmin.SetJoinPolicy)
+	group.PUT("/ban", roomAdmin.BanUser)
+	group.DELETE("/ban", roomAdmin.UnbanUser)
+	group.GET("/bans", roomAdmin.FetchBans)
+	group.GET("/filter_logs", roomAdmin.FetchFilterLogs)
+	group.PUT("/mute", roomAdmin.MuteUser)
+	group.PATCH("/slow_mode", roomAdmin.SetSlowMode)
+	group.PATCH("/settings", roomAdmin.UpdateRoomSettings)
+	group.PATCH("/announcement", roomAdmin.SetAnnouncement)
 }
 
 type RoomAdminController interface {
@@ -29,6 +43,20 @@ type RoomAdminController interface {
 	ConfrimApplication(c *gin.Context)
 	FetchApplications(c *gin.Context)
 	DeleteUser(c *gin.Context)
+	AssignRole(c *gin.Context)
+	CreateInviteLink(c *gin.Context)
+	FetchInviteLinks(c *gin.Context)
+	RevokeInviteLink(c *gin.Context)
+	FetchInviteLinkUses(c *gin.Context)
+	SetJoinPolicy(c *gin.Context)
+	BanUser(c *gin.Context)
+	UnbanUser(c *gin.Context)
+	FetchBans(c *gin.Context)
+	FetchFilterLogs(c *gin.Context)
+	MuteUser(c *gin.Context)
+	SetSlowMode(c *gin.Context)
+	UpdateRoomSettings(c *gin.Context)
+	SetAnnouncement(c *gin.Context)
 }
 
 type roomAdminControllerImpl struct {
@@ -175,3 +203,255 @@ func (r *roomAdminControllerImpl) DeleteUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusNoContent, gin.H{})
 }
+
+func (r *roomAdminControllerImpl) AssignRole(c *gin.Context) {
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().AssignRole(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) CreateInviteLink(c *gin.Context) {
+	var req dto.CreateInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().CreateInviteLink(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) FetchInviteLinks(c *gin.Context) {
+	req := dto.FetchInviteLinksRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().FetchInviteLinks(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) RevokeInviteLink(c *gin.Context) {
+	var req dto.RevokeInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().RevokeInviteLink(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) FetchInviteLinkUses(c *gin.Context) {
+	req := dto.FetchInviteLinkUsesRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().FetchInviteLinkUses(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) SetJoinPolicy(c *gin.Context) {
+	var req dto.SetJoinPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().SetJoinPolicy(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) BanUser(c *gin.Context) {
+	var req dto.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().BanUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) UnbanUser(c *gin.Context) {
+	var req dto.UnbanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().UnbanUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) FetchBans(c *gin.Context) {
+	req := dto.FetchBansRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().FetchBans(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) FetchFilterLogs(c *gin.Context) {
+	req := dto.FetchFilterLogsRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().FetchFilterLogs(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) MuteUser(c *gin.Context) {
+	var req dto.MuteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().MuteUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) SetSlowMode(c *gin.Context) {
+	var req dto.SetSlowModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().SetSlowMode(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomAdminControllerImpl) UpdateRoomSettings(c *gin.Context) {
+	var req dto.UpdateRoomSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomAdminService().UpdateRoomSettings(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomAdminControllerImpl) SetAnnouncement(c *gin.Context) {
+	var req dto.SetAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	_, serviceErr := service.GetRoomAdminService().SetAnnouncement(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
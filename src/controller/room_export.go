@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func roomExportRouter(g *gin.RouterGroup) {
+	group := g.Group("/export")
+	// the token is the credential, so download links work without a session
+	group.GET("/download", roomExport.DownloadRoomExport)
+	group.Use(GetLoginFilter())
+	group.PUT("/", roomExport.CreateRoomExport)
+	group.GET("/", roomExport.FetchRoomExport)
+}
+
+type RoomExportController interface {
+	CreateRoomExport(c *gin.Context)
+	FetchRoomExport(c *gin.Context)
+	DownloadRoomExport(c *gin.Context)
+}
+
+type roomExportControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var roomExport RoomExportController
+
+func init() {
+	roomExport = &roomExportControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+func (r *roomExportControllerImpl) CreateRoomExport(c *gin.Context) {
+	var req dto.CreateRoomExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomExportService().CreateRoomExport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomExportControllerImpl) FetchRoomExport(c *gin.Context) {
+	req := dto.FetchRoomExportRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.AdminUserID = userId
+	res, serviceErr := service.GetRoomExportService().FetchRoomExport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomExportControllerImpl) DownloadRoomExport(c *gin.Context) {
+	req := dto.DownloadRoomExportRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	res, serviceErr := service.GetRoomExportService().DownloadRoomExport(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", res.Filename))
+	c.Data(http.StatusOK, res.ContentType, res.Content)
+}
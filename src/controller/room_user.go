@@ -17,6 +17,9 @@ func roomUserGroupRouter(g *gin.RouterGroup) {
 	group.PUT("/application", roomUser.Apply)
 	group.DELETE("/application", roomUser.DeleteApplication)
 	group.GET("/applications", roomUser.FetchApplications)
+	group.PATCH("/nickname", roomUser.UpdateNickname)
+	group.DELETE("/leave", roomUser.LeaveRoom)
+	group.PUT("/invite_link", roomUser.JoinByInviteLink)
 }
 
 type RoomUserController interface {
@@ -25,6 +28,9 @@ type RoomUserController interface {
 	Apply(c *gin.Context)
 	DeleteApplication(c *gin.Context)
 	FetchApplications(c *gin.Context)
+	UpdateNickname(c *gin.Context)
+	LeaveRoom(c *gin.Context)
+	JoinByInviteLink(c *gin.Context)
 }
 
 type roomUserControllerImpl struct {
@@ -86,13 +92,12 @@ func (r *roomUserControllerImpl) Apply(c *gin.Context) {
 
 	_, userId, _ := GetSessionValue(c)
 	req.UserID = userId
-	_, serviceErr := service.GetRoomUserService().RoomJoinApply(c, &req)
+	res, serviceErr := service.GetRoomUserService().RoomJoinApply(c, &req)
 	if serviceErr != nil {
 		c.JSON(serviceErr.ToJsonResponse())
 		return
 	}
-	c.JSON(http.StatusNoContent, gin.H{})
-
+	c.JSON(http.StatusOK, gin.H{"result": res})
 }
 
 func (r *roomUserControllerImpl) DeleteApplication(c *gin.Context) {
@@ -131,3 +136,57 @@ func (r *roomUserControllerImpl) FetchApplications(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"result": res})
 }
+
+func (r *roomUserControllerImpl) UpdateNickname(c *gin.Context) {
+	var req dto.UpdateNicknameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	_, serviceErr := service.GetRoomUserService().UpdateNickname(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (r *roomUserControllerImpl) LeaveRoom(c *gin.Context) {
+	var req dto.LeaveRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := service.GetRoomUserService().LeaveRoom(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (r *roomUserControllerImpl) JoinByInviteLink(c *gin.Context) {
+	var req dto.JoinByInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := r.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := service.GetRoomUserService().JoinByInviteLink(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
@@ -11,4 +11,10 @@ func MiddlewareInit(g *gin.RouterGroup) {
 	messageGroupRouter(g)
 	stickerRouter(g)
 	WalletRouter(g)
+	roomExportRouter(g)
+	importRouter(g)
+	scheduleRouter(g)
+	bookmarkRouter(g)
+	reportRouter(g)
+	opsRouter(g)
 }
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func scheduleRouter(g *gin.RouterGroup) {
+	group := g.Group("/schedule")
+	group.Use(GetLoginFilter())
+	group.PUT("/message", schedule.ScheduleMessage)
+	group.PUT("/reminder", schedule.SetReminder)
+	group.GET("/", schedule.FetchScheduledJobs)
+	group.PATCH("/", schedule.UpdateScheduledJob)
+	group.DELETE("/", schedule.CancelScheduledJob)
+	group.GET("/reminders/fired", schedule.FetchFiredReminders)
+	group.PATCH("/reminders/ack", schedule.AckReminders)
+}
+
+type ScheduleController interface {
+	ScheduleMessage(c *gin.Context)
+	SetReminder(c *gin.Context)
+	FetchScheduledJobs(c *gin.Context)
+	UpdateScheduledJob(c *gin.Context)
+	CancelScheduledJob(c *gin.Context)
+	FetchFiredReminders(c *gin.Context)
+	AckReminders(c *gin.Context)
+}
+
+type scheduleControllerImpl struct {
+	errWarpper dtoError.ServiceErrorWarpper
+}
+
+var schedule ScheduleController
+
+func init() {
+	schedule = &scheduleControllerImpl{
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+	}
+}
+
+func (s *scheduleControllerImpl) ScheduleMessage(c *gin.Context) {
+	var req dto.ScheduleMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetScheduleService().ScheduleMessage(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (s *scheduleControllerImpl) SetReminder(c *gin.Context) {
+	var req dto.SetReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetScheduleService().SetReminder(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (s *scheduleControllerImpl) FetchScheduledJobs(c *gin.Context) {
+	var req dto.FetchScheduledJobsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetScheduleService().FetchScheduledJobs(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (s *scheduleControllerImpl) UpdateScheduledJob(c *gin.Context) {
+	var req dto.UpdateScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetScheduleService().UpdateScheduledJob(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (s *scheduleControllerImpl) CancelScheduledJob(c *gin.Context) {
+	var req dto.CancelScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	_, serviceErr := service.GetScheduleService().CancelScheduledJob(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (s *scheduleControllerImpl) FetchFiredReminders(c *gin.Context) {
+	var req dto.FetchFiredRemindersRequest
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetScheduleService().FetchFiredReminders(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
+
+func (s *scheduleControllerImpl) AckReminders(c *gin.Context) {
+	var req dto.AckRemindersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := s.errWarpper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+
+	res, serviceErr := service.GetScheduleService().AckReminders(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
@@ -18,6 +18,9 @@ func userGroupRouter(g *gin.RouterGroup) {
 	group.PUT("/reset_password", user.ResetPassword)
 	group.Use(GetLoginFilter())
 	group.GET("/info", user.GetUserInfo)
+	group.PUT("/block", user.BlockUser)
+	group.DELETE("/block", user.UnblockUser)
+	group.GET("/blocks", user.FetchBlockedUsers)
 }
 
 type UserController interface {
@@ -25,6 +28,9 @@ type UserController interface {
 	Login(c *gin.Context)
 	ResetPassword(c *gin.Context)
 	GetUserInfo(c *gin.Context)
+	BlockUser(c *gin.Context)
+	UnblockUser(c *gin.Context)
+	FetchBlockedUsers(c *gin.Context)
 }
 
 type UserControllerImpl struct {
@@ -115,3 +121,57 @@ func (u *UserControllerImpl) GetUserInfo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"result": res})
 }
+
+func (u *UserControllerImpl) BlockUser(c *gin.Context) {
+	var req dto.BlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := u.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	_, serviceErr := service.GetBlockService().BlockUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (u *UserControllerImpl) UnblockUser(c *gin.Context) {
+	var req dto.UnblockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		serviceErr := u.errWarper.NewParseJsonFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	_, serviceErr := service.GetBlockService().UnblockUser(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (u *UserControllerImpl) FetchBlockedUsers(c *gin.Context) {
+	req := dto.FetchBlockedUsersRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		serviceErr := u.errWarper.NewParseQueryFailedServiceError(err)
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+
+	_, userId, _ := GetSessionValue(c)
+	req.UserID = userId
+	res, serviceErr := service.GetBlockService().FetchBlockedUsers(c, &req)
+	if serviceErr != nil {
+		c.JSON(serviceErr.ToJsonResponse())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": res})
+}
@@ -0,0 +1,25 @@
+package dto
+
+type BlockUserRequest struct {
+	UserID        uint64
+	BlockedUserID uint64 `json:"user_id" binding:"required"`
+}
+
+type BlockUserResponse struct{}
+
+type UnblockUserRequest struct {
+	UserID        uint64
+	BlockedUserID uint64 `json:"user_id" binding:"required"`
+}
+
+type UnblockUserResponse struct{}
+
+type FetchBlockedUsersRequest struct {
+	UserID   uint64
+	Page     uint32 `form:"page" binding:"required,gte=1"`
+	PageSize uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type FetchBlockedUsersResponse struct {
+	UserInfos []UserInfo `json:"user_infos" binding:"required"`
+}
@@ -0,0 +1,69 @@
+package dto
+
+type SaveBookmarkRequest struct {
+	UserID    uint64
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	Note      string `json:"note" binding:"max=1000"`
+	Folder    string `json:"folder" binding:"max=64"`
+}
+
+type SaveBookmarkResponse struct {
+	BookmarkID uint64 `json:"bookmark_id"`
+}
+
+type UpdateBookmarkRequest struct {
+	UserID     uint64
+	BookmarkID uint64  `json:"bookmark_id" binding:"required"`
+	Note       *string `json:"note" binding:"omitempty,max=1000"`
+	Folder     *string `json:"folder" binding:"omitempty,max=64"`
+}
+
+type UpdateBookmarkResponse struct{}
+
+type DeleteBookmarkRequest struct {
+	UserID     uint64
+	BookmarkID uint64 `json:"bookmark_id" binding:"required"`
+}
+
+type DeleteBookmarkResponse struct{}
+
+// FetchBookmarksRequest lists every folder when Folder is not given, an empty Folder is the
+// default one.
+type FetchBookmarksRequest struct {
+	UserID uint64
+	Folder *string `form:"folder"`
+	Cursor uint64  `form:"cursor"`
+	Size   int     `form:"size" binding:"omitempty,min=1,max=100"`
+}
+
+// Bookmark carries the saved message, which is left out and MessageDeleted set once the message
+// was deleted.
+type Bookmark struct {
+	BookmarkID     uint64   `json:"bookmark_id"`
+	RoomID         uint64   `json:"room_id"`
+	RoomName       string   `json:"room_name"`
+	Note           string   `json:"note"`
+	Folder         string   `json:"folder"`
+	CreateTime     uint64   `json:"create_time"`
+	MessageDeleted bool     `json:"message_deleted"`
+	Message        *Message `json:"message,omitempty"`
+}
+
+type FetchBookmarksResponse struct {
+	NextCursor uint64     `json:"next_cursor"`
+	Bookmarks  []Bookmark `json:"bookmarks"`
+}
+
+type FetchBookmarkFoldersRequest struct {
+	UserID uint64
+}
+
+type BookmarkFolder struct {
+	Folder string `json:"folder"`
+	Count  int64  `json:"count"`
+}
+
+type FetchBookmarkFoldersResponse struct {
+	Folders []BookmarkFolder `json:"folders"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type CreateImportRequest struct {
+	UserID  uint64
+	Source  string `form:"source" binding:"required,oneof=slack discord"`
+	Archive []byte `form:"-"`
+}
+
+type CreateImportResponse struct {
+	ImportID uint64 `json:"import_id"`
+}
+
+type FetchImportRequest struct {
+	UserID   uint64
+	ImportID uint64 `form:"import_id" binding:"required"`
+}
+
+type FetchImportResponse struct {
+	ImportID     uint64 `json:"import_id"`
+	Source       string `json:"source"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+	RoomCount    int    `json:"room_count"`
+	MessageCount int64  `json:"message_count"`
+	CreateTime   uint64 `json:"create_time"`
+	FinishTime   uint64 `json:"finish_time,omitempty"`
+}
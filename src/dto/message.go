@@ -4,6 +4,9 @@ type AddMessageRequest struct {
 	RoomID  uint64 `json:"room_id" binding:"required"`
 	UserID  uint64
 	Content string `json:"content" binding:"required"`
+	// QuoteMessageID quotes a message of QuoteRoomID, or of RoomID when that is not set.
+	QuoteMessageID uint64 `json:"quote_message_id"`
+	QuoteRoomID    uint64 `json:"quote_room_id"`
 }
 
 type AddMessageResponse struct {
@@ -20,13 +23,143 @@ type FetchMessageRequest struct {
 }
 
 type Message struct {
-	ID        uint64 `json:"id" binding:"required"`
-	UserID    uint64 `json:"user_id" binding:"required"`
-	Content   string `json:"content" binding:"required"`
-	CreatedAt uint64 `json:"create_time" binding:"required"`
+	ID        uint64        `json:"id" binding:"required"`
+	UserID    uint64        `json:"user_id" binding:"required"`
+	Kind      string        `json:"kind" binding:"required"`
+	Content   string        `json:"content" binding:"required"`
+	Event     *SystemEvent  `json:"event,omitempty"`
+	Poll      *Poll         `json:"poll,omitempty"`
+	Reference *Reference    `json:"reference,omitempty"`
+	Previews  []LinkPreview `json:"previews,omitempty"`
+	CreatedAt uint64        `json:"create_time" binding:"required"`
+}
+
+// SystemEvent is the typed payload of a message of kind system.
+type SystemEvent struct {
+	Type         string   `json:"type"`
+	ActorUserID  uint64   `json:"actor_user_id"`
+	TargetUserID uint64   `json:"target_user_id,omitempty"`
+	Changed      []string `json:"changed,omitempty"`
+	MessageID    uint64   `json:"message_id,omitempty"`
+}
+
+// LinkPreview shows up on a message a moment after it was posted, once the link was fetched.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	SiteName    string `json:"site_name"`
 }
 
 type FetchMessageResponse struct {
 	NextTimeCursor uint64    `json:"next_time_cursor" binding:"required"`
 	Messages       []Message `json:"messages" binding:"required"`
 }
+
+type DeleteMessageRequest struct {
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	UserID    uint64
+}
+
+type DeleteMessageResponse struct{}
+
+type PinMessageRequest struct {
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	UserID    uint64
+}
+
+type PinMessageResponse struct{}
+
+type UnpinMessageRequest struct {
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	UserID    uint64
+}
+
+type UnpinMessageResponse struct{}
+
+type FetchPinnedMessagesRequest struct {
+	RoomID uint64 `form:"room_id" binding:"required"`
+	UserID uint64
+}
+
+type PinnedMessage struct {
+	Message
+	PinnedByUserID uint64 `json:"pinned_by_user_id"`
+	PinTime        uint64 `json:"pin_time"`
+}
+
+type FetchPinnedMessagesResponse struct {
+	Pins []PinnedMessage `json:"pins"`
+}
+
+type CreatePollRequest struct {
+	RoomID         uint64 `json:"room_id" binding:"required"`
+	UserID         uint64
+	Question       string   `json:"question" binding:"required"`
+	Options        []string `json:"options" binding:"required,min=2,max=10,dive,required"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	Anonymous      bool     `json:"anonymous"`
+	CloseTime      uint64   `json:"close_time"`
+}
+
+type CreatePollResponse struct {
+	ID        uint64 `json:"id"`
+	CreatedAt uint64 `json:"create_time"`
+}
+
+// VotePollRequest replaces the earlier vote of the user, an empty Options retracts it.
+type VotePollRequest struct {
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	UserID    uint64
+	Options   []int `json:"options"`
+}
+
+type VotePollResponse struct {
+	Poll Poll `json:"poll"`
+}
+
+// Poll is the current result of a poll message. Voters are only listed when the poll is not
+// anonymous, MyVotes always holds the options chosen by the user asking.
+type Poll struct {
+	Options        []PollOption `json:"options"`
+	MultipleChoice bool         `json:"multiple_choice"`
+	Anonymous      bool         `json:"anonymous"`
+	CloseTime      uint64       `json:"close_time,omitempty"`
+	Closed         bool         `json:"closed"`
+	VoterCount     int          `json:"voter_count"`
+	MyVotes        []int        `json:"my_votes"`
+}
+
+type PollOption struct {
+	Text      string   `json:"text"`
+	VoteCount int      `json:"vote_count"`
+	VoterIDs  []uint64 `json:"voter_ids,omitempty"`
+}
+
+// Reference is the original message a message forwards or quotes, Content is only set for quotes.
+type Reference struct {
+	Type       string `json:"type"`
+	RoomID     uint64 `json:"room_id"`
+	MessageID  uint64 `json:"message_id"`
+	UserID     uint64 `json:"user_id"`
+	CreateTime uint64 `json:"create_time"`
+	Content    string `json:"content,omitempty"`
+}
+
+type ForwardMessageRequest struct {
+	UserID          uint64
+	SourceRoomID    uint64 `json:"source_room_id" binding:"required"`
+	SourceMessageID uint64 `json:"source_message_id" binding:"required"`
+	RoomID          uint64 `json:"room_id" binding:"required"`
+}
+
+type ForwardMessageResponse struct {
+	ID        uint64 `json:"id"`
+	CreatedAt uint64 `json:"create_time"`
+	Content   string `json:"content"`
+}
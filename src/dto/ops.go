@@ -0,0 +1,107 @@
+package dto
+
+// SearchUsersRequest matches Keyword against the id, username, name and email of users.
+type SearchUsersRequest struct {
+	AdminUserID   uint64
+	Keyword       string `form:"keyword"`
+	SuspendedOnly bool   `form:"suspended_only"`
+	Page          uint32 `form:"page" binding:"required,gte=1"`
+	PageSize      uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type OpsUser struct {
+	UserID        uint64 `json:"user_id"`
+	Username      string `json:"username"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	PlatformRole  string `json:"platform_role,omitempty"`
+	SuspendTime   uint64 `json:"suspend_time,omitempty"`
+	SuspendReason string `json:"suspend_reason,omitempty"`
+	CreateTime    uint64 `json:"create_time"`
+}
+
+type SearchUsersResponse struct {
+	Users []OpsUser `json:"users"`
+}
+
+type SuspendUserRequest struct {
+	AdminUserID uint64
+	UserID      uint64 `json:"user_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type SuspendUserResponse struct{}
+
+type UnsuspendUserRequest struct {
+	AdminUserID uint64
+	UserID      uint64 `json:"user_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type UnsuspendUserResponse struct{}
+
+// PurgeRoomRequest removes a room and everything in it at once, it can not be restored.
+type PurgeRoomRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type PurgeRoomResponse struct{}
+
+// SetLegalHoldRequest suspends the retention of a room while LegalHold is set. It is not a room
+// setting, the owner must not be able to lift it.
+type SetLegalHoldRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	LegalHold   *bool  `json:"legal_hold" binding:"required"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type SetLegalHoldResponse struct{}
+
+// AdjustWalletRequest adds Amount to the wallet of UserID, a negative Amount takes money away.
+type AdjustWalletRequest struct {
+	AdminUserID uint64
+	UserID      uint64 `json:"user_id" binding:"required"`
+	Amount      int64  `json:"amount" binding:"required,min=-4294967295,max=4294967295"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type AdjustWalletResponse struct {
+	Money uint32 `json:"money"`
+}
+
+type FetchAuditLogsRequest struct {
+	AdminUserID  uint64
+	RoomID       uint64 `form:"room_id"`
+	ActorUserID  uint64 `form:"actor_user_id"`
+	TargetUserID uint64 `form:"target_user_id"`
+	Action       string `form:"action"`
+	Page         uint32 `form:"page" binding:"required,gte=1"`
+	PageSize     uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type AuditLog struct {
+	AuditLogID   uint64 `json:"audit_log_id"`
+	ActorUserID  uint64 `json:"actor_user_id"`
+	Action       string `json:"action"`
+	RoomID       uint64 `json:"room_id,omitempty"`
+	TargetUserID uint64 `json:"target_user_id,omitempty"`
+	MessageID    uint64 `json:"message_id,omitempty"`
+	ReportID     uint64 `json:"report_id,omitempty"`
+	Detail       string `json:"detail"`
+	CreateTime   uint64 `json:"create_time"`
+}
+
+type FetchAuditLogsResponse struct {
+	Logs []AuditLog `json:"logs"`
+}
+
+// FetchEscalatedReportsRequest lists the open escalated reports unless another status is asked for.
+type FetchEscalatedReportsRequest struct {
+	AdminUserID uint64
+	Status      string `form:"status" binding:"omitempty,oneof=open claimed resolved dismissed"`
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+// FileReportRequest reports MessageID, or TargetUserID when no message is given. Escalate asks
+// for the platform operators to look at it too.
+type FileReportRequest struct {
+	UserID       uint64
+	RoomID       uint64 `json:"room_id" binding:"required"`
+	MessageID    uint64 `json:"message_id"`
+	TargetUserID uint64 `json:"target_user_id"`
+	Reason       string `json:"reason" binding:"required,oneof=spam harassment hate violence other"`
+	Detail       string `json:"detail" binding:"max=1000"`
+	Escalate     bool   `json:"escalate"`
+}
+
+type FileReportResponse struct {
+	ReportID uint64 `json:"report_id"`
+}
+
+// FetchReportsRequest lists the open reports of a room unless another status is asked for.
+type FetchReportsRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `form:"room_id" binding:"required"`
+	Status      string `form:"status" binding:"omitempty,oneof=open claimed resolved dismissed"`
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type Report struct {
+	ReportID         uint64 `json:"report_id"`
+	RoomID           uint64 `json:"room_id"`
+	ReporterID       uint64 `json:"reporter_id"`
+	TargetUserID     uint64 `json:"target_user_id"`
+	MessageID        uint64 `json:"message_id,omitempty"`
+	Content          string `json:"content,omitempty"`
+	Reason           string `json:"reason"`
+	Detail           string `json:"detail"`
+	Escalated        bool   `json:"escalated"`
+	Status           string `json:"status"`
+	ClaimedByUserID  uint64 `json:"claimed_by_user_id,omitempty"`
+	ClaimTime        uint64 `json:"claim_time,omitempty"`
+	ResolvedByUserID uint64 `json:"resolved_by_user_id,omitempty"`
+	Action           string `json:"action,omitempty"`
+	Note             string `json:"note,omitempty"`
+	ResolveTime      uint64 `json:"resolve_time,omitempty"`
+	CreateTime       uint64 `json:"create_time"`
+}
+
+type FetchReportsResponse struct {
+	Reports []Report `json:"reports"`
+}
+
+type ClaimReportRequest struct {
+	AdminUserID uint64
+	ReportID    uint64 `json:"report_id" binding:"required"`
+}
+
+type ClaimReportResponse struct{}
+
+// ResolveReportRequest acts on the reported message or user. DurationSecond is how long a mute
+// or ban lasts, a mute needs one and a ban without one is permanent.
+type ResolveReportRequest struct {
+	AdminUserID    uint64
+	ReportID       uint64 `json:"report_id" binding:"required"`
+	Action         string `json:"action" binding:"required,oneof=delete_message mute ban warn"`
+	DurationSecond uint32 `json:"duration_second"`
+	Note           string `json:"note" binding:"max=1000"`
+}
+
+type ResolveReportResponse struct{}
+
+type DismissReportRequest struct {
+	AdminUserID uint64
+	ReportID    uint64 `json:"report_id" binding:"required"`
+	Note        string `json:"note" binding:"max=1000"`
+}
+
+type DismissReportResponse struct{}
@@ -10,6 +10,16 @@ type CreateRoomResponse struct {
 	RoomID uint64 `json:"room_id" binding:"required"`
 }
 
+type OpenDirectRoomRequest struct {
+	UserID       uint64
+	TargetUserID uint64 `json:"user_id" binding:"required"`
+}
+
+type OpenDirectRoomResponse struct {
+	RoomID  uint64 `json:"room_id" binding:"required"`
+	Created bool   `json:"created"`
+}
+
 type GetAvailbleRoomsRequest struct {
 	UserID   uint64
 	Page     uint32 `form:"page" binding:"required,gte=1"`
@@ -25,12 +35,62 @@ type ReadRoomInfoRequest struct {
 	UserID uint64
 }
 
+type UserProfile struct {
+	UserID   uint64 `json:"user_id" binding:"required"`
+	Username string `json:"username" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+}
+
 type ReadRoomInfoResponse struct {
-	ID          uint64   `json:"room_id" binding:"required"`
-	Name        string   `json:"room_name" binding:"required"`
-	AdminUserID uint64   `json:"admin_user_id" binding:"required"`
-	UserIDs     []uint64 `json:"userids" binding:"required"`
-	Description string   `json:"description" binding:"required"`
+	ID             uint64            `json:"room_id" binding:"required"`
+	Name           string            `json:"room_name" binding:"required"`
+	AdminUserID    uint64            `json:"admin_user_id" binding:"required"`
+	UserIDs        []uint64          `json:"userids" binding:"required"`
+	Description    string            `json:"description" binding:"required"`
+	Topic          string            `json:"topic"`
+	AvatarURL      string            `json:"avatar_url"`
+	Capacity       uint32            `json:"capacity"`
+	Type           int32             `json:"room_type"`
+	JoinPolicy     string            `json:"join_policy"`
+	Listed         bool              `json:"listed"`
+	Tags           []string          `json:"tags"`
+	SlowModeSecond uint32            `json:"slow_mode_second"`
+	ArchiveTime    uint64            `json:"archive_time,omitempty"`
+	Mode           string            `json:"mode"`
+	RetentionDay   uint32            `json:"retention_day"`
+	LegalHold      bool              `json:"legal_hold"`
+	FilterAction   string            `json:"filter_action"`
+	FilterWords    []string          `json:"filter_words"`
+	MaxLinks       uint32            `json:"max_links"`
+	Announcement   *RoomAnnouncement `json:"announcement,omitempty"`
+	Counterpart    *UserProfile      `json:"counterpart,omitempty"`
+}
+
+type RoomAnnouncement struct {
+	Content    string `json:"content"`
+	UserID     uint64 `json:"user_id"`
+	UpdateTime uint64 `json:"update_time"`
+}
+
+type SearchRoomDirectoryRequest struct {
+	Keyword  string   `form:"keyword"`
+	Tags     []string `form:"tag"`
+	Page     uint32   `form:"page" binding:"required,gte=1"`
+	PageSize uint32   `form:"page_size" binding:"required,gte=1"`
+}
+
+type RoomDirectoryEntry struct {
+	RoomID      uint64   `json:"room_id"`
+	Name        string   `json:"room_name"`
+	Description string   `json:"description"`
+	JoinPolicy  string   `json:"join_policy"`
+	Tags        []string `json:"tags"`
+	MemberCount uint64   `json:"member_count"`
+}
+
+type SearchRoomDirectoryResponse struct {
+	Rooms []RoomDirectoryEntry `json:"rooms"`
 }
 
 type DeleteRoomRequest struct {
@@ -39,3 +99,34 @@ type DeleteRoomRequest struct {
 }
 
 type DeleteRoomResponse struct{}
+
+type ArchiveRoomRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	Archived    bool   `json:"archived"`
+}
+
+type ArchiveRoomResponse struct{}
+
+type FetchDeletedRoomsRequest struct {
+	AdminUserID uint64
+}
+
+type DeletedRoomInfo struct {
+	RoomID      uint64 `json:"room_id"`
+	Name        string `json:"room_name"`
+	Description string `json:"description"`
+	DeleteTime  uint64 `json:"delete_time"`
+	PurgeTime   uint64 `json:"purge_time"`
+}
+
+type FetchDeletedRoomsResponse struct {
+	Rooms []DeletedRoomInfo `json:"rooms"`
+}
+
+type RestoreRoomRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+}
+
+type RestoreRoomResponse struct{}
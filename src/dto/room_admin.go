@@ -66,3 +66,195 @@ type DeleteUserRequest struct {
 
 type DeleteUserResponse struct {
 }
+
+type AssignRoleRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	UserID      uint64 `json:"user_id" binding:"required"`
+	Role        string `json:"role" binding:"required"`
+}
+
+type AssignRoleResponse struct{}
+
+type CreateInviteLinkRequest struct {
+	RoomID          uint64 `json:"room_id" binding:"required"`
+	AdminUserID     uint64
+	ExpireSecond    uint32 `json:"expire_second"`
+	MaxUses         uint32 `json:"max_uses"`
+	RequireApproval bool   `json:"require_approval"`
+}
+
+type InviteLinkInfo struct {
+	LinkID          uint64 `json:"link_id"`
+	Token           string `json:"token"`
+	CreatorUserID   uint64 `json:"creator_user_id"`
+	ExpireTime      uint64 `json:"expire_time,omitempty"`
+	MaxUses         uint32 `json:"max_uses"`
+	UseCount        uint32 `json:"use_count"`
+	RequireApproval bool   `json:"require_approval"`
+}
+
+type CreateInviteLinkResponse struct {
+	Link InviteLinkInfo `json:"link"`
+}
+
+type FetchInviteLinksRequest struct {
+	RoomID      uint64 `form:"room_id" binding:"required"`
+	AdminUserID uint64
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type FetchInviteLinksResponse struct {
+	RoomID uint64           `json:"room_id"`
+	Links  []InviteLinkInfo `json:"links"`
+}
+
+type RevokeInviteLinkRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	LinkID      uint64 `json:"link_id" binding:"required"`
+}
+
+type RevokeInviteLinkResponse struct{}
+
+type FetchInviteLinkUsesRequest struct {
+	RoomID      uint64 `form:"room_id" binding:"required"`
+	AdminUserID uint64
+	LinkID      uint64 `form:"link_id" binding:"required"`
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type InviteLinkUseInfo struct {
+	UserID   uint64 `json:"user_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Pending  bool   `json:"pending"`
+	JoinTime uint64 `json:"join_time"`
+}
+
+type FetchInviteLinkUsesResponse struct {
+	LinkID uint64              `json:"link_id"`
+	Uses   []InviteLinkUseInfo `json:"uses"`
+}
+
+type SetJoinPolicyRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	JoinPolicy  string   `json:"join_policy" binding:"required"`
+	Listed      bool     `json:"listed"`
+	Tags        []string `json:"tags"`
+}
+
+type SetJoinPolicyResponse struct{}
+
+type BanUserRequest struct {
+	RoomID         uint64 `json:"room_id" binding:"required"`
+	AdminUserID    uint64
+	UserID         uint64 `json:"user_id" binding:"required"`
+	Reason         string `json:"reason"`
+	DurationSecond uint32 `json:"duration_second"`
+}
+
+type BanUserResponse struct{}
+
+type UnbanUserRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	UserID      uint64 `json:"user_id" binding:"required"`
+}
+
+type UnbanUserResponse struct{}
+
+type FetchBansRequest struct {
+	RoomID      uint64 `form:"room_id" binding:"required"`
+	AdminUserID uint64
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type BanInfo struct {
+	UserID         uint64 `json:"user_id"`
+	BannedByUserID uint64 `json:"banned_by_user_id"`
+	Reason         string `json:"reason"`
+	CreateTime     uint64 `json:"create_time"`
+	ExpireTime     uint64 `json:"expire_time,omitempty"`
+}
+
+type FetchBansResponse struct {
+	RoomID uint64    `json:"room_id"`
+	Bans   []BanInfo `json:"bans"`
+}
+
+type FetchFilterLogsRequest struct {
+	RoomID      uint64 `form:"room_id" binding:"required"`
+	AdminUserID uint64
+	Page        uint32 `form:"page" binding:"required,gte=1"`
+	PageSize    uint32 `form:"page_size" binding:"required,gte=1"`
+}
+
+type FilterLogInfo struct {
+	UserID     uint64 `json:"user_id"`
+	Filter     string `json:"filter"`
+	Action     string `json:"action"`
+	Reason     string `json:"reason"`
+	Content    string `json:"content"`
+	CreateTime uint64 `json:"create_time"`
+}
+
+type FetchFilterLogsResponse struct {
+	RoomID uint64          `json:"room_id"`
+	Logs   []FilterLogInfo `json:"logs"`
+}
+
+type MuteUserRequest struct {
+	RoomID         uint64 `json:"room_id" binding:"required"`
+	AdminUserID    uint64
+	UserID         uint64 `json:"user_id" binding:"required"`
+	DurationSecond uint32 `json:"duration_second"`
+}
+
+type MuteUserResponse struct {
+	MutedUntil uint64 `json:"muted_until,omitempty"`
+}
+
+type SetSlowModeRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	Second      uint32 `json:"second" binding:"lte=21600"`
+}
+
+type SetSlowModeResponse struct{}
+
+// UpdateRoomSettingsRequest only changes the fields that are present.
+type UpdateRoomSettingsRequest struct {
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	AdminUserID uint64
+	Name        *string   `json:"room_name" binding:"omitempty,min=1,max=64"`
+	Description *string   `json:"description" binding:"omitempty,max=1024"`
+	Topic       *string   `json:"topic" binding:"omitempty,max=256"`
+	AvatarURL   *string   `json:"avatar_url" binding:"omitempty,url"`
+	Tags        *[]string `json:"tags"`
+	Capacity    *uint32   `json:"capacity"`
+	Mode        *string   `json:"mode" binding:"omitempty,oneof=normal announcement"`
+	// RetentionDay of zero keeps messages forever.
+	RetentionDay *uint32   `json:"retention_day"`
+	FilterAction *string   `json:"filter_action" binding:"omitempty,oneof=off mask reject"`
+	FilterWords  *[]string `json:"filter_words"`
+	// MaxLinks of zero uses the server limit.
+	MaxLinks *uint32 `json:"max_links"`
+}
+
+type UpdateRoomSettingsResponse struct {
+	Changed []string `json:"changed"`
+}
+
+// SetAnnouncementRequest with an empty Content removes the announcement.
+type SetAnnouncementRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	Content     string `json:"content" binding:"max=2000"`
+}
+
+type SetAnnouncementResponse struct{}
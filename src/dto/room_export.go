@@ -0,0 +1,40 @@
+package dto
+
+type CreateRoomExportRequest struct {
+	AdminUserID uint64
+	RoomID      uint64 `json:"room_id" binding:"required"`
+	Format      string `json:"format" binding:"required,oneof=json csv html"`
+}
+
+type CreateRoomExportResponse struct {
+	ExportID uint64 `json:"export_id"`
+}
+
+type FetchRoomExportRequest struct {
+	AdminUserID uint64
+	ExportID    uint64 `form:"export_id" binding:"required"`
+}
+
+// FetchRoomExportResponse carries DownloadPath once the export is done and until it expires.
+type FetchRoomExportResponse struct {
+	ExportID     uint64 `json:"export_id"`
+	RoomID       uint64 `json:"room_id"`
+	Format       string `json:"format"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+	MessageCount int    `json:"message_count"`
+	DownloadPath string `json:"download_path,omitempty"`
+	CreateTime   uint64 `json:"create_time"`
+	FinishTime   uint64 `json:"finish_time,omitempty"`
+	ExpireTime   uint64 `json:"expire_time,omitempty"`
+}
+
+type DownloadRoomExportRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type DownloadRoomExportResponse struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
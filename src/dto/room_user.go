@@ -38,7 +38,9 @@ type RoomJoinApplyRequest struct {
 	UserID uint64
 }
 
-type RoomJoinApplyResponse struct{}
+type RoomJoinApplyResponse struct {
+	Joined bool `json:"joined"`
+}
 
 type RoomJoinApplyCancelRequest struct {
 	RoomID uint64 `json:"room_id" binding:"required"`
@@ -53,7 +55,36 @@ type FetchApplicationByUserRequest struct {
 	PageSize uint32 `form:"page_size" binding:"required,gte=1"`
 }
 
+type UpdateNicknameRequest struct {
+	RoomID   uint64 `json:"room_id" binding:"required"`
+	UserID   uint64
+	Nickname string `json:"nickname" binding:"max=50"`
+}
+
+type UpdateNicknameResponse struct{}
+
 type FetchApplicationByUserResponse struct {
 	UserID    uint64     `json:"user_id" binding:"required"`
 	RoomInfos []RoomInfo `json:"room_infos" binding:"required"`
 }
+
+type LeaveRoomRequest struct {
+	RoomID          uint64 `json:"room_id" binding:"required"`
+	UserID          uint64
+	SuccessorUserID uint64 `json:"successor_user_id"`
+}
+
+type LeaveRoomResponse struct {
+	NewOwnerUserID uint64 `json:"new_owner_user_id"`
+	Archived       bool   `json:"archived"`
+}
+
+type JoinByInviteLinkRequest struct {
+	Token  string `json:"token" binding:"required"`
+	UserID uint64
+}
+
+type JoinByInviteLinkResponse struct {
+	RoomID  uint64 `json:"room_id"`
+	Pending bool   `json:"pending"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+type ScheduleMessageRequest struct {
+	UserID  uint64
+	RoomID  uint64 `json:"room_id" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	RunTime uint64 `json:"run_time" binding:"required"`
+}
+
+type SetReminderRequest struct {
+	UserID    uint64
+	RoomID    uint64 `json:"room_id" binding:"required"`
+	MessageID uint64 `json:"message_id" binding:"required"`
+	Note      string `json:"note"`
+	RunTime   uint64 `json:"run_time" binding:"required"`
+}
+
+type CreateScheduledJobResponse struct {
+	JobID uint64 `json:"job_id"`
+}
+
+// FetchScheduledJobsRequest lists pending jobs unless another status is asked for, fired reminders
+// are the ones with status done.
+type FetchScheduledJobsRequest struct {
+	UserID uint64
+	Kind   string `form:"kind" binding:"omitempty,oneof=message reminder"`
+	Status string `form:"status" binding:"omitempty,oneof=pending running done failed cancelled"`
+}
+
+type ScheduledJob struct {
+	JobID      uint64 `json:"job_id"`
+	Kind       string `json:"kind"`
+	RoomID     uint64 `json:"room_id"`
+	MessageID  uint64 `json:"message_id,omitempty"`
+	Content    string `json:"content"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	RunTime    uint64 `json:"run_time"`
+	FinishTime uint64 `json:"finish_time,omitempty"`
+	CreateTime uint64 `json:"create_time"`
+}
+
+type FetchScheduledJobsResponse struct {
+	Jobs []ScheduledJob `json:"jobs"`
+}
+
+type FetchFiredRemindersRequest struct {
+	UserID uint64
+}
+
+// FiredReminder is a reminder that went off, Message is nil when the message was deleted since.
+type FiredReminder struct {
+	JobID    uint64   `json:"job_id"`
+	RoomID   uint64   `json:"room_id"`
+	Note     string   `json:"note"`
+	FireTime uint64   `json:"fire_time"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// FetchFiredRemindersResponse holds the oldest unacknowledged reminders, clients poll it and
+// acknowledge what they showed so the next call returns the rest.
+type FetchFiredRemindersResponse struct {
+	Reminders []FiredReminder `json:"reminders"`
+}
+
+type AckRemindersRequest struct {
+	UserID uint64
+	JobIDs []uint64 `json:"job_ids" binding:"required,min=1,max=100"`
+}
+
+type AckRemindersResponse struct {
+	Acked int64 `json:"acked"`
+}
+
+// UpdateScheduledJobRequest changes the fields that are set, Content is the message of a scheduled
+// message and the note of a reminder.
+type UpdateScheduledJobRequest struct {
+	UserID  uint64
+	JobID   uint64  `json:"job_id" binding:"required"`
+	Content *string `json:"content"`
+	RunTime *uint64 `json:"run_time"`
+}
+
+type UpdateScheduledJobResponse struct{}
+
+type CancelScheduledJobRequest struct {
+	UserID uint64
+	JobID  uint64 `json:"job_id" binding:"required"`
+}
+
+type CancelScheduledJobResponse struct{}
@@ -1,6 +1,8 @@
 package dtoError
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,6 +11,7 @@ type ServiceError struct {
 	ErrorCode      int64
 	InternalError  error
 	ExtrenalReason string
+	Detail         gin.H // optional machine readable data for the client, omitted when nil
 }
 
 func (s *ServiceError) ToJsonResponse() (statusCode int, H *gin.H) {
@@ -17,6 +20,9 @@ func (s *ServiceError) ToJsonResponse() (statusCode int, H *gin.H) {
 		"errorCode": s.ErrorCode,
 		"reason":    s.ExtrenalReason,
 	}
+	if s.Detail != nil {
+		(*H)["detail"] = s.Detail
+	}
 	return
 }
 
@@ -41,9 +47,34 @@ const (
 	NotAdminInRoom    = 20001
 	UserAlreadyInRoom = 20003
 	RoomNameUsed      = 20004
-
-	UserIsInvited    = 30000
-	UserIsNotInvited = 30001
+	RoomIsDirect      = 20005
+	DirectRoomSelf    = 20006
+	PermissionDenied  = 20007
+	InvalidRoomRole   = 20008
+	RoomInviteOnly    = 20009
+	InvalidJoinPolicy = 20010
+	UserIsBanned      = 20011
+	UserNotBanned     = 20012
+	UserIsMuted       = 20013
+	RoomIsFull        = 20014
+	RoomIsArchived    = 20015
+	RoomNotRestorable = 20016
+	MessageNotExist   = 20017
+	PinLimitReached   = 20018
+	MessageNotPinned  = 20019
+	ExportNotExist    = 20020
+	ExportLinkInvalid = 20021
+	MessageNotPoll    = 20022
+	PollClosed        = 20023
+	InvalidPollVote   = 20024
+	InvalidReference  = 20025
+	MessageRejected   = 20026
+	RoomUnderHold     = 20027
+
+	UserIsInvited      = 30000
+	UserIsNotInvited   = 30001
+	InviteLinkInvalid  = 30002
+	InviteLinkNotExist = 30003
 
 	UserHasApplied = 40000
 	UserNotApply   = 40001
@@ -54,6 +85,31 @@ const (
 	UserMoneyNotEnough    = 60000
 	UserNotCharged        = 60001
 	UserChargeMoneyExcess = 60002
+
+	UserIsBlocked      = 70000
+	UserAlreadyBlocked = 70001
+	UserNotBlocked     = 70002
+	CannotBlockSelf    = 70003
+
+	SlowModeActive       = 80000
+	MessageBurstExceeded = 80001
+
+	ImportNotExist = 90000
+
+	ScheduledJobNotExist   = 100000
+	ScheduledJobNotPending = 100001
+	InvalidScheduleTime    = 100002
+
+	BookmarkNotExist = 110000
+
+	ReportNotExist = 120000
+	ReportClaimed  = 120001
+	ReportClosed   = 120002
+	InvalidReport  = 120003
+
+	UserSuspended     = 130000
+	NotPlatformAdmin  = 130001
+	CannotSuspendSelf = 130002
 )
 
 type ServiceErrorWarpper interface {
@@ -75,9 +131,47 @@ type ServiceErrorWarpper interface {
 	NewNotAdminOfRoomError(adminID uint64, roomID uint64) *ServiceError
 	NewUserAlreadyInRoomError(userID uint64, roomID uint64) *ServiceError
 	NewRoomNameUsedError(roomName string) *ServiceError
+	NewRoomIsDirectError(roomID uint64) *ServiceError
+	NewDirectRoomSelfError(userID uint64) *ServiceError
+	NewPermissionDeniedError(userID uint64, roomID uint64, permission string) *ServiceError
+	NewInvalidRoomRoleError(role string) *ServiceError
+	NewRoomInviteOnlyError(roomID uint64) *ServiceError
+	NewInvalidJoinPolicyError(joinPolicy string) *ServiceError
+	NewUserIsBannedError(userID uint64, roomID uint64) *ServiceError
+	NewUserNotBannedError(userID uint64, roomID uint64) *ServiceError
+	NewUserIsMutedError(userID uint64, roomID uint64, mutedUntil time.Time) *ServiceError
+	NewRoomIsFullError(roomID uint64) *ServiceError
+	NewRoomIsArchivedError(roomID uint64) *ServiceError
+	NewRoomNotRestorableError(roomID uint64) *ServiceError
+	NewMessageNotExistError(messageID uint64, roomID uint64) *ServiceError
+	NewPinLimitReachedError(roomID uint64, limit int) *ServiceError
+	NewMessageNotPinnedError(messageID uint64, roomID uint64) *ServiceError
+	NewExportNotExistError(exportID uint64) *ServiceError
+	NewExportLinkInvalidError() *ServiceError
+	NewMessageNotPollError(messageID uint64, roomID uint64) *ServiceError
+	NewPollClosedError(messageID uint64) *ServiceError
+	NewInvalidPollVoteError(messageID uint64, reason string) *ServiceError
+	NewInvalidReferenceError(messageID uint64, roomID uint64, reason string) *ServiceError
+	NewMessageRejectedError(roomID uint64, filter string, reason string) *ServiceError
+	NewRoomUnderHoldError(roomID uint64) *ServiceError
+	NewImportNotExistError(importID uint64) *ServiceError
+	NewScheduledJobNotExistError(jobID uint64) *ServiceError
+	NewScheduledJobNotPendingError(jobID uint64) *ServiceError
+	NewInvalidScheduleTimeError(runAt time.Time) *ServiceError
+	NewBookmarkNotExistError(bookmarkID uint64) *ServiceError
+	NewReportNotExistError(reportID uint64) *ServiceError
+	NewReportClaimedError(reportID uint64, claimedByUserID uint64) *ServiceError
+	NewReportClosedError(reportID uint64, status string) *ServiceError
+	NewInvalidReportError(reason string) *ServiceError
+	NewUserSuspendedError(userID uint64) *ServiceError
+	NewNotPlatformAdminError(userID uint64) *ServiceError
+	NewCannotSuspendSelfError(userID uint64) *ServiceError
 
 	NewUserIsInvitedError(userID uint64, roomID uint64) *ServiceError
 	NewUserIsNotInvitedError(userID uint64, roomID uint64) *ServiceError
+	NewInviteLinkInvalidError() *ServiceError
+	NewInviteLinkNotExistError(linkID uint64, roomID uint64) *ServiceError
+	NewInviteTokenFailedError(err error) *ServiceError
 
 	NewUserApplyError(userID uint64, roomID uint64) *ServiceError
 	NewUserNotApplyError(userID uint64, roomID uint64) *ServiceError
@@ -88,4 +182,12 @@ type ServiceErrorWarpper interface {
 	NewUserMoneyNotEnoughError(userID uint64) *ServiceError
 	NewUserNotChargedError(userID uint64) *ServiceError
 	NewUserChargeMoneyExcessError(userID uint64, charge uint32, min uint32, max uint32) *ServiceError
+
+	NewUserIsBlockedError(userID uint64, blockedUserID uint64) *ServiceError
+	NewUserAlreadyBlockedError(userID uint64, blockedUserID uint64) *ServiceError
+	NewUserNotBlockedError(userID uint64, blockedUserID uint64) *ServiceError
+	NewCannotBlockSelfError(userID uint64) *ServiceError
+
+	NewSlowModeActiveError(roomID uint64, retryAfter time.Duration) *ServiceError
+	NewMessageBurstExceededError(roomID uint64, retryAfter time.Duration) *ServiceError
 }
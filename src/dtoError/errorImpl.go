@@ -3,6 +3,9 @@ package dtoError
 import (
 	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 var s ServiceErrorWarpper = &ServiceErrorWarpperImpl{}
@@ -180,6 +183,33 @@ func (s *ServiceErrorWarpperImpl) NewUserIsNotInvitedError(userID uint64, roomID
 	}
 }
 
+func (s *ServiceErrorWarpperImpl) NewInviteLinkInvalidError() *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusGone,
+		ErrorCode:      InviteLinkInvalid,
+		InternalError:  nil,
+		ExtrenalReason: "invite link is invalid, expired, revoked or used up",
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInviteLinkNotExistError(linkID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      InviteLinkNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("invite link %d does not exist in room %d", linkID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInviteTokenFailedError(err error) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusInternalServerError,
+		ErrorCode:      UnKnown,
+		InternalError:  err,
+		ExtrenalReason: "generate invite token failed",
+	}
+}
+
 func (s *ServiceErrorWarpperImpl) NewUserNotApplyError(userID uint64, roomID uint64) *ServiceError {
 	return &ServiceError{
 		StatusCode:     http.StatusConflict,
@@ -207,6 +237,323 @@ func (s *ServiceErrorWarpperImpl) NewRoomNameUsedError(roomName string) *Service
 	}
 }
 
+func (s *ServiceErrorWarpperImpl) NewRoomIsDirectError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      RoomIsDirect,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d is a direct message room", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewDirectRoomSelfError(userID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      DirectRoomSelf,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d can not open a direct message room with themselves", userID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewPermissionDeniedError(userID uint64, roomID uint64, permission string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      PermissionDenied,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d has no %s permission in room %d", userID, permission, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidRoomRoleError(role string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidRoomRole,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("role %s can not be assigned", role),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewRoomInviteOnlyError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      RoomInviteOnly,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d can only be joined by invitation", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidJoinPolicyError(joinPolicy string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidJoinPolicy,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("join policy %s is not supported", joinPolicy),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserIsBannedError(userID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      UserIsBanned,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d is banned from room %d", userID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserNotBannedError(userID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      UserNotBanned,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d is not banned from room %d", userID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserIsMutedError(userID uint64, roomID uint64, mutedUntil time.Time) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      UserIsMuted,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d is muted in room %d until %s", userID, roomID, mutedUntil.Format(time.RFC3339)),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewRoomIsFullError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      RoomIsFull,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d has reached its member capacity", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewRoomIsArchivedError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      RoomIsArchived,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d is archived and read only", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewRoomNotRestorableError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      RoomNotRestorable,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d is not deleted or its retention window has passed", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewMessageNotExistError(messageID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      MessageNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("message %d does not exist in room %d", messageID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewPinLimitReachedError(roomID uint64, limit int) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      PinLimitReached,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d already has %d pinned messages", roomID, limit),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewMessageNotPinnedError(messageID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      MessageNotPinned,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("message %d is not pinned in room %d", messageID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewExportNotExistError(exportID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      ExportNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("export %d does not exist", exportID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewExportLinkInvalidError() *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusGone,
+		ErrorCode:      ExportLinkInvalid,
+		InternalError:  nil,
+		ExtrenalReason: "export link is unknown, not ready yet or expired",
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewMessageNotPollError(messageID uint64, roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      MessageNotPoll,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("message %d in room %d is not a poll", messageID, roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewPollClosedError(messageID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      PollClosed,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("poll %d is closed", messageID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidPollVoteError(messageID uint64, reason string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidPollVote,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("invalid vote on poll %d: %s", messageID, reason),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidReferenceError(messageID uint64, roomID uint64, reason string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidReference,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("message %d in room %d can not be referenced: %s", messageID, roomID, reason),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewMessageRejectedError(roomID uint64, filter string, reason string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusUnprocessableEntity,
+		ErrorCode:      MessageRejected,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("message rejected in room %d: %s", roomID, reason),
+		Detail:         gin.H{"filter": filter},
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewRoomUnderHoldError(roomID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      RoomUnderHold,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d is under legal hold and can not be deleted", roomID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewImportNotExistError(importID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      ImportNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("import %d does not exist", importID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewScheduledJobNotExistError(jobID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      ScheduledJobNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("scheduled job %d does not exist", jobID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewScheduledJobNotPendingError(jobID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      ScheduledJobNotPending,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("scheduled job %d already ran or was cancelled", jobID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidScheduleTimeError(runAt time.Time) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidScheduleTime,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("run time %s must be in the future and within a year", runAt.Format(time.RFC3339)),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewBookmarkNotExistError(bookmarkID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      BookmarkNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("bookmark %d does not exist", bookmarkID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewReportNotExistError(reportID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusNotFound,
+		ErrorCode:      ReportNotExist,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("report %d does not exist", reportID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewReportClaimedError(reportID uint64, claimedByUserID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      ReportClaimed,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("report %d is claimed by user %d", reportID, claimedByUserID),
+		Detail:         gin.H{"claimed_by_user_id": claimedByUserID},
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewReportClosedError(reportID uint64, status string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      ReportClosed,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("report %d is already %s", reportID, status),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewInvalidReportError(reason string) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      InvalidReport,
+		InternalError:  nil,
+		ExtrenalReason: reason,
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserSuspendedError(userID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      UserSuspended,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d is suspended", userID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewNotPlatformAdminError(userID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      NotPlatformAdmin,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d is not a platform admin", userID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewCannotSuspendSelfError(userID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      CannotSuspendSelf,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d can not suspend themselves", userID),
+	}
+}
+
 func (s *ServiceErrorWarpperImpl) NewUsernameExist(username string) *ServiceError {
 	return &ServiceError{
 		StatusCode:     http.StatusConflict,
@@ -234,6 +581,62 @@ func (s *ServiceErrorWarpperImpl) NewUserNotExist(Id uint64) *ServiceError {
 	}
 }
 
+func (s *ServiceErrorWarpperImpl) NewUserIsBlockedError(userID uint64, blockedUserID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusForbidden,
+		ErrorCode:      UserIsBlocked,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d has blocked user %d", userID, blockedUserID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserAlreadyBlockedError(userID uint64, blockedUserID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      UserAlreadyBlocked,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d has already blocked user %d", userID, blockedUserID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewUserNotBlockedError(userID uint64, blockedUserID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusConflict,
+		ErrorCode:      UserNotBlocked,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d does not block user %d", userID, blockedUserID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewCannotBlockSelfError(userID uint64) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusBadRequest,
+		ErrorCode:      CannotBlockSelf,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("user %d can not block themselves", userID),
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewSlowModeActiveError(roomID uint64, retryAfter time.Duration) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusTooManyRequests,
+		ErrorCode:      SlowModeActive,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("room %d is in slow mode", roomID),
+		Detail:         gin.H{"retry_after_ms": retryAfter.Milliseconds()},
+	}
+}
+
+func (s *ServiceErrorWarpperImpl) NewMessageBurstExceededError(roomID uint64, retryAfter time.Duration) *ServiceError {
+	return &ServiceError{
+		StatusCode:     http.StatusTooManyRequests,
+		ErrorCode:      MessageBurstExceeded,
+		InternalError:  nil,
+		ExtrenalReason: fmt.Sprintf("too many messages sent to room %d", roomID),
+		Detail:         gin.H{"retry_after_ms": retryAfter.Milliseconds()},
+	}
+}
+
 func GetServiceErrorWarpper() ServiceErrorWarpper {
 	return s
 }
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"ChatRoomAPI/src/model"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+// Render encodes the transcript in the given format, see ContentType for the matching content type.
+func Render(format string, transcript *Transcript) ([]byte, error) {
+	switch format {
+	case model.ExportFormatJSON:
+		return json.MarshalIndent(transcript, "", "  ")
+	case model.ExportFormatCSV:
+		return renderCSV(transcript)
+	case model.ExportFormatHTML:
+		return renderHTML(transcript)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// ContentType is the content type of what Render produces for format.
+func ContentType(format string) string {
+	switch format {
+	case model.ExportFormatJSON:
+		return "application/json"
+	case model.ExportFormatCSV:
+		return "text/csv"
+	case model.ExportFormatHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+func renderCSV(transcript *Transcript) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write([]string{"id", "create_time", "user_id", "username", "name", "kind", "content"}); err != nil {
+		return nil, err
+	}
+	for _, message := range transcript.Messages {
+		record := []string{
+			strconv.FormatUint(message.ID, 10),
+			message.CreateTime.Format(time.RFC3339),
+			strconv.FormatUint(message.UserID, 10),
+			message.Username,
+			message.Name,
+			message.Kind,
+			message.Content,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// transcriptPage is self contained, the styles are inline so the file opens anywhere.
+var transcriptPage = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.RoomName}} transcript</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 50em; color: #222; }
+.message { padding: .4em 0; border-bottom: 1px solid #eee; }
+.meta { color: #777; font-size: .85em; }
+.system { color: #777; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.RoomName}}</h1>
+<p class="meta">Room {{.RoomID}}, exported {{.ExportedAt.Format "2006-01-02 15:04:05 MST"}}, {{len .Messages}} messages</p>
+{{range .Messages}}<div class="message{{if eq .Kind "system"}} system{{end}}">
+<div class="meta">{{.CreateTime.Format "2006-01-02 15:04:05"}} {{if .Name}}{{.Name}} ({{.Username}}){{else}}user {{.UserID}}{{end}}</div>
+<div>{{.Content}}</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+func renderHTML(transcript *Transcript) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := transcriptPage.Execute(&buffer, transcript); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
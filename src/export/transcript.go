@@ -0,0 +1,174 @@
+package export
+
+import (
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pageSize is the number of messages read per FetchMessagesAfter call while walking a room.
+const pageSize = 500
+
+type Sticker struct {
+	SetID     uint64 `json:"sticker_set_id"`
+	StickerID uint64 `json:"sticker_id"`
+	SetName   string `json:"sticker_set_name"`
+	Name      string `json:"sticker_name"`
+}
+
+type Message struct {
+	ID         uint64    `json:"id"`
+	UserID     uint64    `json:"user_id"`
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	Content    string    `json:"content"`
+	Stickers   []Sticker `json:"stickers,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// Transcript is the whole history of a room in chronological order, with authors and stickers resolved.
+type Transcript struct {
+	RoomID     uint64    `json:"room_id"`
+	RoomName   string    `json:"room_name"`
+	ExportedAt time.Time `json:"exported_at"`
+	Messages   []Message `json:"messages"`
+}
+
+type Builder struct {
+	roomRepo    repository.RoomRepository
+	messageRepo repository.MessageRepository
+	userRepo    repository.AccountRepository
+	stickerRepo repository.StickerRepository
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		roomRepo:    repository.GetRoomRepository(),
+		messageRepo: repository.GetMessageRepository(),
+		userRepo:    repository.GetAccountRepository(),
+		stickerRepo: repository.GetStickerRepository(),
+	}
+}
+
+// Build walks the room history page by page from the first message on.
+func (b *Builder) Build(ctx context.Context, roomID uint64) (*Transcript, error) {
+	room, err := b.roomRepo.ReadRoomInfo(ctx, roomID)
+	if err != nil {
+		return nil, err
+	} else if room == nil {
+		return nil, fmt.Errorf("room %d does not exist", roomID)
+	}
+
+	transcript := Transcript{RoomID: room.Id, RoomName: room.Name, ExportedAt: time.Now(), Messages: []Message{}}
+	users := map[uint64]*model.User{}
+	stickerSets := map[uint64]*model.StickerSet{}
+	cursorTime, cursorID := time.Unix(0, 0), uint64(0)
+	for {
+		messages, err := b.messageRepo.FetchMessagesAfter(ctx, roomID, cursorTime, cursorID, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.loadUsers(ctx, messages, users); err != nil {
+			return nil, err
+		}
+
+		for _, message := range messages {
+			content, stickers, err := b.renderStickers(ctx, message.Content, stickerSets)
+			if err != nil {
+				return nil, err
+			}
+			entry := Message{
+				ID:         message.ID,
+				UserID:     message.UserID,
+				Kind:       message.Kind,
+				Content:    content,
+				Stickers:   stickers,
+				CreateTime: message.CreatedAt,
+			}
+			if user, ok := users[message.UserID]; ok {
+				entry.Username = user.Username
+				entry.Name = user.Name
+			}
+			transcript.Messages = append(transcript.Messages, entry)
+		}
+
+		if len(messages) < pageSize {
+			break
+		}
+		last := messages[len(messages)-1]
+		cursorTime, cursorID = last.CreatedAt, last.ID
+	}
+	return &transcript, nil
+}
+
+// loadUsers adds the authors of messages that are not in users yet.
+func (b *Builder) loadUsers(ctx context.Context, messages []*model.Message, users map[uint64]*model.User) error {
+	missing := []uint64{}
+	for _, message := range messages {
+		if _, ok := users[message.UserID]; !ok && message.UserID != 0 {
+			users[message.UserID] = nil
+			missing = append(missing, message.UserID)
+		}
+	}
+
+	found, err := b.userRepo.UsersInfo(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for _, user := range found {
+		users[user.Id] = user
+	}
+	for _, id := range missing {
+		if users[id] == nil {
+			delete(users, id)
+		}
+	}
+	return nil
+}
+
+// renderStickers replaces every sticker::<set id>::<sticker id> reference with a readable
+// [sticker <set>/<name>] and returns the stickers it resolved.
+func (b *Builder) renderStickers(ctx context.Context, content string, stickerSets map[uint64]*model.StickerSet) (string, []Sticker, error) {
+	chunks := strings.Split(content, " ")
+	stickers := []Sticker{}
+	for i, chunk := range chunks {
+		subchunks := strings.Split(chunk, "::")
+		if len(subchunks) != 3 || subchunks[0] != "sticker" {
+			continue
+		}
+		setID, err := strconv.ParseUint(subchunks[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		stickerID, err := strconv.ParseUint(subchunks[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		set, ok := stickerSets[setID]
+		if !ok {
+			set, _, err = b.stickerRepo.GetStickerSetInfo(ctx, setID)
+			if err != nil {
+				return "", nil, err
+			}
+			stickerSets[setID] = set
+		}
+		if set == nil {
+			continue
+		}
+
+		for _, sticker := range set.Stickers {
+			if sticker.Id == stickerID {
+				stickers = append(stickers, Sticker{SetID: setID, StickerID: stickerID, SetName: set.Name, Name: sticker.Name})
+				chunks[i] = fmt.Sprintf("[sticker %s/%s]", set.Name, sticker.Name)
+				break
+			}
+		}
+	}
+	return strings.Join(chunks, " "), stickers, nil
+}
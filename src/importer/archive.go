@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"ChatRoomAPI/src/model"
+	"fmt"
+	"time"
+)
+
+// Archive is the source independent content of an export archive.
+type Archive struct {
+	Users []User
+	Rooms []Room
+}
+
+type User struct {
+	ExternalID string
+	Username   string
+	Name       string
+	Email      string
+}
+
+type Room struct {
+	ExternalID  string
+	Name        string
+	Description string
+	Messages    []Message
+}
+
+// Message ExternalID only has to be unique within its room.
+type Message struct {
+	ExternalID     string
+	UserExternalID string
+	Content        string
+	CreatedAt      time.Time
+}
+
+// Parse reads an archive exported by source, a Slack ZIP or a Discord JSON file.
+func Parse(source string, data []byte) (*Archive, error) {
+	switch source {
+	case model.ImportSourceSlack:
+		return parseSlack(data)
+	case model.ImportSourceDiscord:
+		return parseDiscord(data)
+	}
+	return nil, fmt.Errorf("unknown import source %q", source)
+}
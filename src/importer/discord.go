@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// discordExport is the JSON layout written by DiscordChatExporter, one channel per file.
+type discordExport struct {
+	Channel struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Topic string `json:"topic"`
+	} `json:"channel"`
+	Messages []struct {
+		ID        string    `json:"id"`
+		Type      string    `json:"type"`
+		Timestamp time.Time `json:"timestamp"`
+		Content   string    `json:"content"`
+		Author    struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Nickname string `json:"nickname"`
+		} `json:"author"`
+	} `json:"messages"`
+}
+
+// discordTypes are the message types that carry conversation.
+var discordTypes = map[string]bool{
+	"Default": true,
+	"Reply":   true,
+}
+
+// parseDiscord reads a channel export. Discord does not export e-mail addresses, so its users
+// always become placeholders.
+func parseDiscord(data []byte) (*Archive, error) {
+	export := discordExport{}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("discord export is not valid json: %w", err)
+	} else if export.Channel.ID == "" {
+		return nil, fmt.Errorf("discord export has no channel")
+	}
+
+	archive := Archive{}
+	room := Room{ExternalID: export.Channel.ID, Name: export.Channel.Name, Description: export.Channel.Topic}
+	seen := map[string]bool{}
+	for _, message := range export.Messages {
+		if !discordTypes[message.Type] || message.Author.ID == "" {
+			continue
+		}
+		if !seen[message.Author.ID] {
+			seen[message.Author.ID] = true
+			name := message.Author.Nickname
+			if name == "" {
+				name = message.Author.Name
+			}
+			archive.Users = append(archive.Users, User{ExternalID: message.Author.ID, Username: message.Author.Name, Name: name})
+		}
+		room.Messages = append(room.Messages, Message{
+			ExternalID:     message.ID,
+			UserExternalID: message.Author.ID,
+			Content:        message.Content,
+			CreatedAt:      message.Timestamp,
+		})
+	}
+	archive.Rooms = append(archive.Rooms, room)
+	return &archive, nil
+}
@@ -0,0 +1,249 @@
+package importer
+
+import (
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// messageBatchSize is the number of messages inserted per statement.
+const messageBatchSize = 1000
+
+type Result struct {
+	RoomCount    int
+	MessageCount int64
+}
+
+// Importer writes a parsed archive into the database. Every step is keyed by the ids of the source
+// system, through import mappings for users and rooms and the external id of messages, so running
+// the same archive again only adds what is missing. That is also how an interrupted import resumes.
+type Importer struct {
+	importRepo  repository.ImportRepository
+	userRepo    repository.AccountRepository
+	roomRepo    repository.RoomRepository
+	messageRepo repository.MessageRepository
+}
+
+func New() *Importer {
+	return &Importer{
+		importRepo:  repository.GetImportRepository(),
+		userRepo:    repository.GetAccountRepository(),
+		roomRepo:    repository.GetRoomRepository(),
+		messageRepo: repository.GetMessageRepository(),
+	}
+}
+
+// importUser is a source user resolved to a local account. Placeholder accounts are created by the
+// import and join the rooms they wrote in, existing accounts matched by e-mail keep their history
+// but are not added to rooms behind their back.
+type importUser struct {
+	localID     uint64
+	placeholder bool
+}
+
+// importRun is the state of one Run. Anyone can upload an archive naming any e-mail address, so
+// matching source users to existing accounts by e-mail is only done for platform admins, everyone
+// else gets placeholders.
+type importRun struct {
+	source      string
+	ownerUserID uint64
+	matchEmail  bool
+	users       map[string]*importUser
+}
+
+// Run imports data exported by source, the rooms it creates are owned by ownerUserID.
+func (i *Importer) Run(ctx context.Context, source string, data []byte, ownerUserID uint64) (*Result, error) {
+	archive, err := Parse(source, data)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := i.userRepo.AccountStatus(ctx, ownerUserID)
+	if err != nil {
+		return nil, err
+	} else if owner == nil {
+		return nil, fmt.Errorf("user %d does not exist", ownerUserID)
+	}
+	run := &importRun{
+		source:      source,
+		ownerUserID: ownerUserID,
+		matchEmail:  owner.PlatformRole == model.PlatformRoleAdmin,
+		users:       map[string]*importUser{},
+	}
+
+	for _, user := range archive.Users {
+		if _, err := i.resolveUser(ctx, run, user); err != nil {
+			return nil, err
+		}
+	}
+
+	result := Result{}
+	for _, room := range archive.Rooms {
+		inserted, err := i.importRoom(ctx, run, room)
+		if err != nil {
+			return nil, fmt.Errorf("room %s: %w", room.Name, err)
+		}
+		result.RoomCount++
+		result.MessageCount += inserted
+	}
+	return &result, nil
+}
+
+func (i *Importer) resolveUser(ctx context.Context, run *importRun, user User) (*importUser, error) {
+	if resolved, ok := run.users[user.ExternalID]; ok {
+		return resolved, nil
+	}
+
+	localID, found, err := i.importRepo.FindMapping(ctx, run.ownerUserID, run.source, model.ImportMappingUser, user.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &importUser{localID: localID}
+	if found {
+		// only placeholders carry the source prefix in their username
+		account, err := i.userRepo.UserInfo(ctx, localID)
+		if err != nil {
+			return nil, err
+		}
+		resolved.placeholder = strings.HasPrefix(account.Username, placeholderPrefix(run.source))
+		run.users[user.ExternalID] = resolved
+		return resolved, nil
+	}
+
+	if run.matchEmail && user.Email != "" {
+		account, exist, err := i.userRepo.SelectUserByEmail(ctx, user.Email)
+		if err != nil {
+			return nil, err
+		} else if exist {
+			resolved.localID = account.Id
+		}
+	}
+	if resolved.localID == 0 {
+		name := user.Name
+		if name == "" {
+			name = user.Username
+		}
+		// an empty password hash never matches, placeholders can not log in. The owner is part of the
+		// username, so two imports of the same workspace get their own placeholders.
+		username := fmt.Sprintf("%s%d_%s", placeholderPrefix(run.source), run.ownerUserID, user.ExternalID)
+		account, created, err := i.userRepo.UserRegister(ctx, username, "", name, "", time.Time{})
+		if err != nil {
+			return nil, err
+		} else if !created {
+			// the mapping lookup above missed, so the name belongs to an account that is not ours
+			return nil, fmt.Errorf("placeholder username %s is already taken", username)
+		}
+		resolved.localID = account.Id
+		resolved.placeholder = true
+	}
+
+	mapping := model.ImportMapping{
+		OwnerUserID: run.ownerUserID,
+		Source:      run.source,
+		Kind:        model.ImportMappingUser,
+		ExternalID:  user.ExternalID,
+		LocalID:     resolved.localID,
+	}
+	if err := i.importRepo.SaveMapping(ctx, &mapping); err != nil {
+		return nil, err
+	}
+	run.users[user.ExternalID] = resolved
+	return resolved, nil
+}
+
+func placeholderPrefix(source string) string {
+	return source + "_"
+}
+
+func (i *Importer) importRoom(ctx context.Context, run *importRun, room Room) (int64, error) {
+	roomID, err := i.resolveRoom(ctx, run, room)
+	if err != nil {
+		return 0, err
+	}
+
+	var inserted int64
+	members := map[uint64]bool{}
+	batch := make([]*model.Message, 0, messageBatchSize)
+	for _, message := range room.Messages {
+		user, err := i.resolveUser(ctx, run, User{ExternalID: message.UserExternalID, Username: message.UserExternalID})
+		if err != nil {
+			return inserted, err
+		}
+		if user.placeholder && !members[user.localID] {
+			if _, err := i.roomRepo.AddUser(ctx, roomID, user.localID); err != nil {
+				return inserted, err
+			}
+			members[user.localID] = true
+		}
+
+		externalID := message.ExternalID
+		batch = append(batch, &model.Message{
+			RoomID:     roomID,
+			UserID:     user.localID,
+			Kind:       model.MessageKindUser,
+			Content:    message.Content,
+			ExternalID: &externalID,
+			Base:       model.Base{CreatedAt: message.CreatedAt, UpdatedAt: message.CreatedAt},
+		})
+		if len(batch) == messageBatchSize {
+			count, err := i.messageRepo.ImportMessages(ctx, batch)
+			if err != nil {
+				return inserted, err
+			}
+			inserted += count
+			batch = batch[:0]
+		}
+	}
+
+	count, err := i.messageRepo.ImportMessages(ctx, batch)
+	return inserted + count, err
+}
+
+// resolveRoom finds the room of an earlier run or creates it. The room and its mapping are written
+// in one transaction, so a crash in between can not leave an unmapped copy behind.
+func (i *Importer) resolveRoom(ctx context.Context, run *importRun, room Room) (uint64, error) {
+	roomID, found, err := i.importRepo.FindMapping(ctx, run.ownerUserID, run.source, model.ImportMappingRoom, room.ExternalID)
+	if err != nil || found {
+		return roomID, err
+	}
+
+	name := strings.TrimSpace(room.Name)
+	if name == "" || strings.HasPrefix(name, repository.DirectRoomNamePrefix) {
+		name = run.source + "-" + room.ExternalID
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	for attempt := 0; ; attempt++ {
+		candidate := name
+		if attempt == 1 {
+			candidate = fmt.Sprintf("%s (%s)", name, run.source)
+		} else if attempt > 1 {
+			candidate = fmt.Sprintf("%s (%s %d)", name, run.source, attempt)
+		}
+
+		created, ok, err := i.roomRepo.CreateRoom(txContext, run.ownerUserID, candidate, room.Description)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		} else if ok {
+			roomID = created.Id
+			break
+		}
+	}
+
+	mapping := model.ImportMapping{
+		OwnerUserID: run.ownerUserID,
+		Source:      run.source,
+		Kind:        model.ImportMappingRoom,
+		ExternalID:  room.ExternalID,
+		LocalID:     roomID,
+	}
+	if err := i.importRepo.SaveMapping(txContext, &mapping); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return roomID, tx.Commit().Error
+}
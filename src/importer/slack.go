@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A small zip can inflate to gigabytes. Every file of an export is capped, and so is the sum of what
+// is read, both by the size the zip declares and by what is actually inflated.
+const (
+	maxSlackFileSize   = 64 << 20
+	maxSlackExportSize = 1 << 30
+)
+
+type slackUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Profile  struct {
+		RealName string `json:"real_name"`
+		Email    string `json:"email"`
+	} `json:"profile"`
+}
+
+type slackChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Purpose struct {
+		Value string `json:"value"`
+	} `json:"purpose"`
+	Topic struct {
+		Value string `json:"value"`
+	} `json:"topic"`
+}
+
+type slackMessage struct {
+	Type    string `json:"type"`
+	Subtype string `json:"subtype"`
+	User    string `json:"user"`
+	Text    string `json:"text"`
+	TS      string `json:"ts"`
+}
+
+// slackSubtypes are the message subtypes that carry conversation, joins, topic changes and the
+// like are left out.
+var slackSubtypes = map[string]bool{
+	"":                 true,
+	"me_message":       true,
+	"thread_broadcast": true,
+	"file_share":       true,
+}
+
+// parseSlack reads a workspace export: users.json, channels.json and one folder of daily files
+// per channel.
+func parseSlack(data []byte) (*Archive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("slack export is not a zip file: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range reader.File {
+		files[strings.TrimPrefix(path.Clean(file.Name), "/")] = file
+	}
+
+	remaining := int64(maxSlackExportSize)
+	users := []slackUser{}
+	if err := readZipJSON(files, "users.json", &users, &remaining); err != nil {
+		return nil, err
+	}
+	channels := []slackChannel{}
+	if err := readZipJSON(files, "channels.json", &channels, &remaining); err != nil {
+		return nil, err
+	}
+
+	archive := Archive{}
+	for _, user := range users {
+		name := user.Profile.RealName
+		if name == "" {
+			name = user.RealName
+		}
+		archive.Users = append(archive.Users, User{ExternalID: user.ID, Username: user.Name, Name: name, Email: user.Profile.Email})
+	}
+
+	for _, channel := range channels {
+		description := channel.Purpose.Value
+		if description == "" {
+			description = channel.Topic.Value
+		}
+		room := Room{ExternalID: channel.ID, Name: channel.Name, Description: description}
+
+		days := []string{}
+		for name := range files {
+			if path.Dir(name) == channel.Name && path.Ext(name) == ".json" {
+				days = append(days, name)
+			}
+		}
+		// daily files are named by date, so name order is time order
+		sort.Strings(days)
+
+		for _, day := range days {
+			messages := []slackMessage{}
+			if err := readZipJSON(files, day, &messages, &remaining); err != nil {
+				return nil, err
+			}
+			for _, message := range messages {
+				if message.Type != "message" || !slackSubtypes[message.Subtype] || message.User == "" {
+					continue
+				}
+				createdAt, err := parseSlackTS(message.TS)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", day, err)
+				}
+				room.Messages = append(room.Messages, Message{
+					ExternalID:     message.TS,
+					UserExternalID: message.User,
+					Content:        message.Text,
+					CreatedAt:      createdAt,
+				})
+			}
+		}
+		archive.Rooms = append(archive.Rooms, room)
+	}
+	return &archive, nil
+}
+
+// readZipJSON decodes one file of the export and takes what it read off remaining.
+func readZipJSON(files map[string]*zip.File, name string, target any, remaining *int64) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("slack export has no %s", name)
+	}
+	limit := min(int64(maxSlackFileSize), *remaining)
+	if file.UncompressedSize64 > uint64(limit) {
+		return fmt.Errorf("%s is too large", name)
+	}
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	// the declared size can lie, read one byte past the limit to notice
+	data, err := io.ReadAll(io.LimitReader(content, limit+1))
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	} else if int64(len(data)) > limit {
+		return fmt.Errorf("%s is too large", name)
+	}
+	*remaining -= int64(len(data))
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// parseSlackTS converts a "<seconds>.<micro seconds>" timestamp, it is also the message id in its channel.
+func parseSlackTS(ts string) (time.Time, error) {
+	seconds, micros, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid slack ts %q", ts)
+	}
+	var usec int64
+	if micros != "" {
+		if usec, err = strconv.ParseInt(micros, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid slack ts %q", ts)
+		}
+	}
+	return time.Unix(sec, usec*int64(time.Microsecond)), nil
+}
@@ -46,6 +46,10 @@ type config struct {
 				Second     int `yaml:"second"`
 				MaxRequest int `yaml:"max_request"`
 			} `yaml:"repeat"`
+			Message struct {
+				Second     int `yaml:"second"`
+				MaxRequest int `yaml:"max_request"`
+			} `yaml:"message"`
 		} `yaml:"rate_limit"`
 	} `yaml:"server"`
 	Database struct {
@@ -70,6 +74,24 @@ type config struct {
 		Host string `yaml:"host"`
 		Port int    `yaml:"port"`
 	}
+	Worker struct {
+		DefaultIntervalSecond int            `yaml:"default_interval_second"`
+		IntervalSecond        map[string]int `yaml:"interval_second"`
+	} `yaml:"worker"`
+	Retention struct {
+		DeletedRoomDay int `yaml:"deleted_room_day"`
+	} `yaml:"retention"`
+	Export struct {
+		ExpireHour int `yaml:"expire_hour"`
+	} `yaml:"export"`
+	Filter struct {
+		Words    []string `yaml:"words"`
+		MaxLinks int      `yaml:"max_links"`
+		Flood    struct {
+			Second    int `yaml:"second"`
+			MaxRepeat int `yaml:"max_repeat"`
+		} `yaml:"flood"`
+	} `yaml:"filter"`
 }
 
 type allConfigs struct {
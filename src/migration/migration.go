@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"ChatRoomAPI/src/model"
+
+	"gorm.io/gorm"
+)
+
+// models lists every table owned by the service, AutoMigrate creates missing tables and columns.
+var models = []any{
+	&model.User{},
+	&model.Room{},
+	&model.RoomMember{},
+	&model.Message{},
+	&model.ApplyRecord{},
+	&model.InviteRecord{},
+	&model.StickerSet{},
+	&model.Sticker{},
+	&model.StickerSetUserMapping{},
+	&model.Wallet{},
+	&model.WalletLog{},
+	&model.UserBlock{},
+	&model.InviteLink{},
+	&model.InviteLinkUse{},
+	&model.RoomBan{},
+	&model.PinnedMessage{},
+	&model.RoomExport{},
+	&model.ImportJob{},
+	&model.ImportMapping{},
+	&model.ScheduledJob{},
+	&model.PollVote{},
+	&model.Bookmark{},
+	&model.LinkPreview{},
+	&model.FilterLog{},
+	&model.Report{},
+	&model.AuditLog{},
+}
+
+func Run(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := scopeImportMappings(tx); err != nil {
+			return err
+		}
+		if err := renameDuplicateDirectRooms(tx); err != nil {
+			return err
+		}
+		if err := tx.AutoMigrate(models...); err != nil {
+			return err
+		}
+		return convertRoomUserIDs(tx)
+	})
+}
+
+// scopeImportMappings runs before AutoMigrate, which would add owner_user_id but keep the old
+// idx_import_mapping without it. Mappings written before were shared by every importer and nothing
+// says whose they are, so they are dropped and the next import of an archive maps it afresh.
+func scopeImportMappings(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&model.ImportMapping{}) || tx.Migrator().HasColumn(&model.ImportMapping{}, "owner_user_id") {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM import_mappings").Error; err != nil {
+		return err
+	}
+	return tx.Migrator().DropIndex(&model.ImportMapping{}, "idx_import_mapping")
+}
+
+// renameDuplicateDirectRooms runs before AutoMigrate creates idx_direct_room_name. Concurrent
+// requests could create a pair's direct room twice before the index existed, the oldest keeps the
+// name and the others get their id appended, so they stay listed for both members with their messages.
+func renameDuplicateDirectRooms(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&model.Room{}) || tx.Migrator().HasIndex(&model.Room{}, "idx_direct_room_name") {
+		return nil
+	}
+	return tx.Exec(`
+		UPDATE rooms SET name = name || '::' || id
+		WHERE type = ? AND delete_time IS NULL AND id NOT IN (
+			SELECT min(id) FROM rooms WHERE type = ? AND delete_time IS NULL GROUP BY name
+		)`,
+		model.RoomTypeDirect, model.RoomTypeDirect,
+	).Error
+}
+
+// convertRoomUserIDs moves the legacy rooms.user_ids bigint array into room_members and then drops
+// the column. It is a no-op once the column is gone, so running the migration twice is safe.
+func convertRoomUserIDs(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&model.Room{}, "user_ids") {
+		return nil
+	}
+
+	err := tx.Exec(`
+		INSERT INTO room_members (room_id, user_id, role, nickname, joined_at, update_time)
+		SELECT DISTINCT rooms.id, members.user_id,
+			CASE WHEN members.user_id = rooms.admin_user_id THEN ? ELSE ? END,
+			'', rooms.create_time, now()
+		FROM rooms
+		CROSS JOIN LATERAL unnest(array_append(rooms.user_ids, rooms.admin_user_id)) AS members(user_id)
+		WHERE members.user_id <> 0
+		ON CONFLICT (room_id, user_id) DO NOTHING`,
+		model.RoomRoleOwner, model.RoomRoleMember,
+	).Error
+	if err != nil {
+		return err
+	}
+	return tx.Migrator().DropColumn(&model.Room{}, "user_ids")
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+const (
+	AuditActionReportClaimed   = "report_claimed"
+	AuditActionReportResolved  = "report_resolved"
+	AuditActionReportDismissed = "report_dismissed"
+	AuditActionUserSuspended   = "user_suspended"
+	AuditActionUserUnsuspended = "user_unsuspended"
+	AuditActionRoomPurged      = "room_purged"
+	AuditActionLegalHoldSet    = "legal_hold_set"
+	AuditActionLegalHoldLifted = "legal_hold_lifted"
+	AuditActionWalletAdjusted  = "wallet_adjusted"
+)
+
+// AuditLog is an append only record of a moderation or ops decision. The ids that do not apply to the
+// action are zero, Detail holds what was done, e.g. the action a report was resolved with.
+type AuditLog struct {
+	Id           uint64    `gorm:"primaryKey;column:id"`
+	ActorUserID  uint64    `gorm:"not null;index;column:actor_user_id"`
+	Action       string    `gorm:"not null;column:action"`
+	RoomID       uint64    `gorm:"not null;default:0;index;column:room_id"`
+	TargetUserID uint64    `gorm:"not null;default:0;column:target_user_id"`
+	MessageID    uint64    `gorm:"not null;default:0;column:message_id"`
+	ReportID     uint64    `gorm:"not null;default:0;column:report_id"`
+	Detail       string    `gorm:"not null;default:'';column:detail"`
+	CreatedAt    time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
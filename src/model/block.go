@@ -0,0 +1,8 @@
+package model
+
+type UserBlock struct {
+	Id            uint64 `gorm:"primaryKey;column:id"`
+	UserID        uint64 `gorm:"not null;column:user_id;uniqueIndex:idx_user_block_pair"`
+	BlockedUserID uint64 `gorm:"not null;column:blocked_user_id;uniqueIndex:idx_user_block_pair"`
+	Base
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Bookmark is a message a user saved for later, Folder is empty for the default folder.
+type Bookmark struct {
+	Id        uint64    `gorm:"primaryKey;column:id"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_bookmark;column:user_id"`
+	MessageID uint64    `gorm:"not null;uniqueIndex:idx_bookmark;column:message_id"`
+	RoomID    uint64    `gorm:"not null;index;column:room_id"`
+	Note      string    `gorm:"not null;default:'';column:note"`
+	Folder    string    `gorm:"not null;default:'';column:folder"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
+
+// BookmarkRecord is a bookmark with its room and message, the message fields are nil once the
+// message was deleted.
+type BookmarkRecord struct {
+	Id               uint64
+	MessageID        uint64
+	RoomID           uint64
+	RoomName         string
+	Note             string
+	Folder           string
+	CreatedAt        time.Time
+	MessageUserID    *uint64
+	MessageKind      *string
+	MessageContent   *string
+	MessageCreatedAt *time.Time
+}
+
+type BookmarkFolderRecord struct {
+	Folder string
+	Count  int64
+}
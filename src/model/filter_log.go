@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	FilterActionOff    = "off"
+	FilterActionMask   = "mask"
+	FilterActionReject = "reject"
+)
+
+// FilterLog records a message the content filters masked or rejected, Content is the text as the
+// user sent it.
+type FilterLog struct {
+	Id        uint64    `gorm:"primaryKey;column:id"`
+	RoomID    uint64    `gorm:"not null;index;column:room_id"`
+	UserID    uint64    `gorm:"not null;column:user_id"`
+	Filter    string    `gorm:"not null;column:filter"`
+	Action    string    `gorm:"not null;column:action"`
+	Reason    string    `gorm:"not null;default:'';column:reason"`
+	Content   string    `gorm:"not null;column:content"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
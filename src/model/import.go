@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+const (
+	ImportSourceSlack   = "slack"
+	ImportSourceDiscord = "discord"
+)
+
+const (
+	ImportStatusPending = "pending"
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+	ImportStatusFailed  = "failed"
+)
+
+const (
+	ImportMappingUser = "user"
+	ImportMappingRoom = "room"
+)
+
+// ImportJob is a chat history import. The uploaded archive is kept until the job is done, so a job
+// interrupted half way is simply run again.
+type ImportJob struct {
+	Id                uint64     `gorm:"primaryKey;column:id"`
+	Source            string     `gorm:"not null;column:source"`
+	RequestedByUserID uint64     `gorm:"not null;index;column:requested_by_user_id"`
+	Status            string     `gorm:"not null;default:pending;index;column:status"`
+	Error             string     `gorm:"not null;default:'';column:error"`
+	Archive           []byte     `gorm:"type:bytea;column:archive"`
+	RoomCount         int        `gorm:"not null;default:0;column:room_count"`
+	MessageCount      int64      `gorm:"not null;default:0;column:message_count"`
+	FinishedAt        *time.Time `gorm:"column:finish_time"`
+	CreatedAt         time.Time  `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt         time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
+
+// ImportMapping remembers which local user or room an id of the source system became, it is what
+// makes a repeated import reuse instead of duplicate. Mappings belong to the user who ran the import,
+// the same external ids from someone else's archive never resolve to their rooms or users.
+type ImportMapping struct {
+	Id          uint64    `gorm:"primaryKey;column:id"`
+	OwnerUserID uint64    `gorm:"not null;uniqueIndex:idx_import_mapping;column:owner_user_id"`
+	Source      string    `gorm:"not null;uniqueIndex:idx_import_mapping;column:source"`
+	Kind        string    `gorm:"not null;uniqueIndex:idx_import_mapping;column:kind"`
+	ExternalID  string    `gorm:"not null;uniqueIndex:idx_import_mapping;column:external_id"`
+	LocalID     uint64    `gorm:"not null;column:local_id"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// InviteLink is a shareable token that lets anyone holding it join a room. MaxUses of 0 means unlimited.
+type InviteLink struct {
+	Id              uint64     `gorm:"primaryKey;column:id"`
+	RoomID          uint64     `gorm:"not null;index;column:room_id"`
+	Token           string     `gorm:"not null;uniqueIndex;column:token"`
+	CreatorUserID   uint64     `gorm:"not null;column:creator_user_id"`
+	ExpiresAt       *time.Time `gorm:"column:expire_time"`
+	MaxUses         uint32     `gorm:"not null;default:0;column:max_uses"`
+	UseCount        uint32     `gorm:"not null;default:0;column:use_count"`
+	RequireApproval bool       `gorm:"not null;default:false;column:require_approval"`
+	RevokedAt       *time.Time `gorm:"column:revoke_time"`
+	Base
+}
+
+type InviteLinkUse struct {
+	Id           uint64 `gorm:"primaryKey;column:id"`
+	InviteLinkID uint64 `gorm:"not null;index;column:invite_link_id"`
+	UserID       uint64 `gorm:"not null;column:user_id"`
+	Pending      bool   `gorm:"not null;default:false;column:pending"`
+	Base
+}
+
+type InviteLinkUseRecord struct {
+	Id         uint64
+	UserId     uint64
+	Name       string
+	Email      string
+	Pending    bool
+	CreateTime time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// LinkPreview is the preview of a link in a message. It is written in the background once the
+// message is committed, so a message can show up before its previews do.
+type LinkPreview struct {
+	Id          uint64    `gorm:"primaryKey;column:id"`
+	MessageID   uint64    `gorm:"not null;uniqueIndex:idx_link_preview;column:message_id"`
+	URL         string    `gorm:"not null;uniqueIndex:idx_link_preview;column:url"`
+	Title       string    `gorm:"not null;default:'';column:title"`
+	Description string    `gorm:"not null;default:'';column:description"`
+	ImageURL    string    `gorm:"not null;default:'';column:image_url"`
+	SiteName    string    `gorm:"not null;default:'';column:site_name"`
+	CreatedAt   time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
@@ -1,9 +1,56 @@
 package model
 
+import "time"
+
+const (
+	MessageKindUser   = "user"
+	MessageKindSystem = "system"
+	MessageKindPoll   = "poll"
+)
+
+const (
+	SystemEventMemberJoined       = "member_joined"
+	SystemEventMemberLeft         = "member_left"
+	SystemEventMemberKicked       = "member_kicked"
+	SystemEventMemberBanned       = "member_banned"
+	SystemEventOwnerChanged       = "owner_changed"
+	SystemEventInvitationAccepted = "invitation_accepted"
+	SystemEventSettingsUpdated    = "settings_updated"
+	SystemEventRoomArchived       = "room_archived"
+	SystemEventRoomUnarchived     = "room_unarchived"
+	SystemEventRoomRestored       = "room_restored"
+	SystemEventMessagePinned      = "message_pinned"
+	SystemEventAnnouncementSet    = "announcement_updated"
+	SystemEventMemberWarned       = "member_warned"
+)
+
+const (
+	MessageReferenceForward = "forward"
+	MessageReferenceQuote   = "quote"
+)
+
+// MessageReference is the payload of a user message that forwards or quotes another one. It keeps
+// the attribution of the original message, and for a quote the quoted text as it was at the time.
+type MessageReference struct {
+	Type      string    `json:"type"`
+	RoomID    uint64    `json:"room_id"`
+	MessageID uint64    `json:"message_id"`
+	UserID    uint64    `json:"user_id"`
+	CreatedAt time.Time `json:"create_time"`
+	Content   string    `json:"content,omitempty"`
+}
+
+// Message of kind system is written by the service itself, UserID is then the user who caused it
+// and Payload holds the event as JSON. A poll keeps its question as content and a PollPayload as
+// payload. A forwarded or quoting user message holds a MessageReference. ExternalID is only set
+// on imported messages and keeps a repeated import from inserting them twice.
 type Message struct {
-	ID      uint64 `gorm:"primaryKey;column:id"`
-	RoomID  uint64 `gorm:"not null;column:room_id"`
-	UserID  uint64 `gorm:"not null;column:user_id"`
-	Content string `gorm:"not null;column:content"`
+	ID         uint64  `gorm:"primaryKey;column:id"`
+	RoomID     uint64  `gorm:"not null;uniqueIndex:idx_message_external;column:room_id"`
+	UserID     uint64  `gorm:"not null;column:user_id"`
+	Kind       string  `gorm:"not null;default:user;column:kind"`
+	Content    string  `gorm:"not null;column:content"`
+	Payload    string  `gorm:"not null;default:'';column:payload"`
+	ExternalID *string `gorm:"uniqueIndex:idx_message_external;column:external_id"`
 	Base
 }
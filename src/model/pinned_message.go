@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// PinnedMessage marks a message as pinned in its room. It only references the message, so edits
+// show through, and it is removed together with the message.
+type PinnedMessage struct {
+	Id             uint64    `gorm:"primaryKey;column:id"`
+	RoomID         uint64    `gorm:"not null;index;column:room_id"`
+	MessageID      uint64    `gorm:"not null;uniqueIndex;column:message_id"`
+	PinnedByUserID uint64    `gorm:"not null;column:pinned_by_user_id"`
+	CreatedAt      time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
+
+type PinnedMessageRecord struct {
+	MessageID      uint64
+	UserID         uint64
+	Kind           string
+	Content        string
+	PinnedByUserID uint64
+	PinnedAt       time.Time
+	CreatedAt      time.Time
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// PollPayload is stored as JSON in the payload of a message of kind poll. Votes are kept apart in
+// poll_votes and counted when the messages are fetched.
+type PollPayload struct {
+	Options        []string   `json:"options"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	Anonymous      bool       `json:"anonymous"`
+	CloseTime      *time.Time `json:"close_time,omitempty"`
+}
+
+// Closed reports whether the poll stopped taking votes at now.
+func (p *PollPayload) Closed(now time.Time) bool {
+	return p.CloseTime != nil && !now.Before(*p.CloseTime)
+}
+
+// PollVote is one chosen option, a multiple choice vote is stored as several rows.
+type PollVote struct {
+	Id        uint64    `gorm:"primaryKey;column:id"`
+	MessageID uint64    `gorm:"not null;uniqueIndex:idx_poll_vote;column:message_id"`
+	UserID    uint64    `gorm:"not null;uniqueIndex:idx_poll_vote;column:user_id"`
+	Option    int       `gorm:"not null;uniqueIndex:idx_poll_vote;column:option_index"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime:nano;column:create_time"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusClaimed   = "claimed"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+const (
+	ReportActionDeleteMessage = "delete_message"
+	ReportActionMute          = "mute"
+	ReportActionBan           = "ban"
+	ReportActionWarn          = "warn"
+)
+
+// Report is a member flagging a message or a user of a room. MessageID is zero when the user as a
+// whole is reported, Content keeps the reported text so moderators see it after an edit or delete.
+// Escalated reports are meant for the platform operators as well as the room moderators.
+type Report struct {
+	Id               uint64     `gorm:"primaryKey;column:id"`
+	RoomID           uint64     `gorm:"not null;index:idx_report_queue,priority:1;column:room_id"`
+	ReporterID       uint64     `gorm:"not null;index;column:reporter_id"`
+	TargetUserID     uint64     `gorm:"not null;index;column:target_user_id"`
+	MessageID        uint64     `gorm:"not null;default:0;column:message_id"`
+	Content          string     `gorm:"not null;default:'';column:content"`
+	Reason           string     `gorm:"not null;column:reason"`
+	Detail           string     `gorm:"not null;default:'';column:detail"`
+	Escalated        bool       `gorm:"not null;default:false;column:escalated"`
+	Status           string     `gorm:"not null;default:open;index:idx_report_queue,priority:2;column:status"`
+	ClaimedByUserID  uint64     `gorm:"not null;default:0;column:claimed_by_user_id"`
+	ClaimedAt        *time.Time `gorm:"column:claim_time"`
+	ResolvedByUserID uint64     `gorm:"not null;default:0;column:resolved_by_user_id"`
+	Action           string     `gorm:"not null;default:'';column:action"`
+	Note             string     `gorm:"not null;default:'';column:note"`
+	ResolvedAt       *time.Time `gorm:"column:resolve_time"`
+	CreatedAt        time.Time  `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt        time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
@@ -1,14 +1,65 @@
 package model
 
 import (
+	"time"
+
 	"github.com/lib/pq"
 )
 
+const (
+	RoomTypeGroup  int32 = 0
+	RoomTypeDirect int32 = 1
+)
+
+const (
+	JoinPolicyOpen       = "open"
+	JoinPolicyApproval   = "approval"
+	JoinPolicyInviteOnly = "invite_only"
+)
+
+// In an announcement room only members with the broadcast permission can post, everybody else reads.
+const (
+	RoomModeNormal       = "normal"
+	RoomModeAnnouncement = "announcement"
+)
+
 type Room struct {
-	Id          uint64        `gorm:"primaryKey;column:id"`
-	AdminUserID uint64        `gorm:"not null;column:admin_user_id"`
-	Name        string        `gorm:"not null;column:name"`
-	UserIDs     pq.Int64Array `gorm:"not null;column:user_ids;type:bigint[]"`
-	Description string        `gorm:"not null;column:description"`
+	Id             uint64         `gorm:"primaryKey;column:id"`
+	AdminUserID    uint64         `gorm:"not null;column:admin_user_id"`
+	Name           string         `gorm:"not null;uniqueIndex:idx_direct_room_name,where:type = 1 and delete_time is null;column:name"`
+	Description    string         `gorm:"not null;column:description"`
+	Topic          string         `gorm:"not null;default:'';column:topic"`
+	AvatarURL      string         `gorm:"not null;default:'';column:avatar_url"`
+	Capacity       uint32         `gorm:"not null;default:0;column:capacity"`
+	Type           int32          `gorm:"not null;default:0;column:type"`
+	JoinPolicy     string         `gorm:"not null;default:approval;column:join_policy"`
+	Listed         bool           `gorm:"not null;default:false;column:listed"`
+	Tags           pq.StringArray `gorm:"type:text[];not null;default:'{}';column:tags"`
+	SlowModeSecond uint32         `gorm:"not null;default:0;column:slow_mode_second"`
+	ArchivedAt     *time.Time     `gorm:"column:archive_time"`
+	Mode           string         `gorm:"not null;default:normal;column:mode"`
+	// RetentionDay of zero keeps messages forever, LegalHold suspends retention while it is set.
+	RetentionDay uint32 `gorm:"not null;default:0;column:retention_day"`
+	LegalHold    bool   `gorm:"not null;default:false;column:legal_hold"`
+	// FilterAction decides what happens to a message with a filtered word, FilterWords are added
+	// to the server word list and MaxLinks of zero falls back to the server limit.
+	FilterAction string         `gorm:"not null;default:mask;column:filter_action"`
+	FilterWords  pq.StringArray `gorm:"type:text[];not null;default:'{}';column:filter_words"`
+	MaxLinks     uint32         `gorm:"not null;default:0;column:max_links"`
+
+	// Announcement is the banner shown on top of the room, empty when there is none.
+	Announcement       string        `gorm:"not null;default:'';column:announcement"`
+	AnnouncementUserID uint64        `gorm:"not null;default:0;column:announcement_user_id"`
+	AnnouncementTime   *time.Time    `gorm:"column:announcement_time"`
+	Members            []*RoomMember `gorm:"foreignKey:RoomID"`
 	Base
 }
+
+type RoomDirectoryRecord struct {
+	Id          uint64
+	Name        string
+	Description string
+	JoinPolicy  string
+	Tags        pq.StringArray `gorm:"type:text[]"`
+	MemberCount uint64
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// RoomBan keeps a user out of a room until ExpiresAt, a nil ExpiresAt is permanent. Rows are hard
+// deleted when the ban is lifted or expires.
+type RoomBan struct {
+	Id             uint64     `gorm:"primaryKey;column:id"`
+	RoomID         uint64     `gorm:"not null;uniqueIndex:idx_room_ban;column:room_id"`
+	UserID         uint64     `gorm:"not null;uniqueIndex:idx_room_ban;column:user_id"`
+	BannedByUserID uint64     `gorm:"not null;column:banned_by_user_id"`
+	Reason         string     `gorm:"not null;default:'';column:reason"`
+	ExpiresAt      *time.Time `gorm:"index;column:expire_time"`
+	CreatedAt      time.Time  `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt      time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
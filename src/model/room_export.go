@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatHTML = "html"
+)
+
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusDone    = "done"
+	ExportStatusFailed  = "failed"
+)
+
+// RoomExport is a transcript export job. The worker fills Content once the job is done, the file can
+// then be downloaded with Token until ExpiresAt.
+type RoomExport struct {
+	Id                uint64     `gorm:"primaryKey;column:id"`
+	RoomID            uint64     `gorm:"not null;index;column:room_id"`
+	RequestedByUserID uint64     `gorm:"not null;column:requested_by_user_id"`
+	Format            string     `gorm:"not null;column:format"`
+	Status            string     `gorm:"not null;default:pending;index;column:status"`
+	Error             string     `gorm:"not null;default:'';column:error"`
+	Token             string     `gorm:"not null;uniqueIndex;column:token"`
+	Content           []byte     `gorm:"type:bytea;column:content"`
+	MessageCount      int        `gorm:"not null;default:0;column:message_count"`
+	ExpiresAt         *time.Time `gorm:"index;column:expire_time"`
+	FinishedAt        *time.Time `gorm:"column:finish_time"`
+	CreatedAt         time.Time  `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt         time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
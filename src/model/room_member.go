@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	RoomRoleOwner     = "owner"
+	RoomRoleModerator = "moderator"
+	RoomRoleMember    = "member"
+	RoomRoleReadOnly  = "readonly"
+)
+
+// RoomMember rows are hard deleted when a user leaves, so a user can join the same room again.
+type RoomMember struct {
+	Id         uint64     `gorm:"primaryKey;column:id"`
+	RoomID     uint64     `gorm:"not null;column:room_id;uniqueIndex:idx_room_member"`
+	UserID     uint64     `gorm:"not null;column:user_id;uniqueIndex:idx_room_member;index"`
+	Role       string     `gorm:"not null;default:member;column:role"`
+	Nickname   string     `gorm:"not null;default:'';column:nickname"`
+	MutedUntil *time.Time `gorm:"column:muted_until"`
+	JoinedAt   time.Time  `gorm:"not null;autoCreateTime:nano;column:joined_at"`
+	UpdatedAt  time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	ScheduledJobKindMessage  = "message"
+	ScheduledJobKindReminder = "reminder"
+)
+
+const (
+	ScheduledJobStatusPending   = "pending"
+	ScheduledJobStatusRunning   = "running"
+	ScheduledJobStatusDone      = "done"
+	ScheduledJobStatusFailed    = "failed"
+	ScheduledJobStatusCancelled = "cancelled"
+)
+
+// ScheduledJob is a message to be posted by UserID at RunAt, or a reminder UserID set on MessageID.
+// A reminder is done once RunAt passed, it then waits among the user's fired reminders until they
+// acknowledge it and AckedAt is set.
+type ScheduledJob struct {
+	Id         uint64     `gorm:"primaryKey;column:id"`
+	Kind       string     `gorm:"not null;column:kind"`
+	UserID     uint64     `gorm:"not null;index;column:user_id"`
+	RoomID     uint64     `gorm:"not null;index;column:room_id"`
+	MessageID  uint64     `gorm:"not null;default:0;column:message_id"`
+	Content    string     `gorm:"not null;default:'';column:content"`
+	RunAt      time.Time  `gorm:"not null;index:idx_scheduled_job_due,priority:2;column:run_time"`
+	Status     string     `gorm:"not null;default:pending;index:idx_scheduled_job_due,priority:1;column:status"`
+	Error      string     `gorm:"not null;default:'';column:error"`
+	FinishedAt *time.Time `gorm:"column:finish_time"`
+	AckedAt    *time.Time `gorm:"column:ack_time"`
+	CreatedAt  time.Time  `gorm:"not null;autoCreateTime:nano;column:create_time"`
+	UpdatedAt  time.Time  `gorm:"not null;autoUpdateTime:nano;column:update_time"`
+}
@@ -2,6 +2,9 @@ package model
 
 import "time"
 
+// PlatformRoleAdmin may use the ops API, every other user has an empty platform role.
+const PlatformRoleAdmin = "admin"
+
 type User struct {
 	Id       uint64    `gorm:"primaryKey;column:id"`
 	Username string    `gorm:"not null;column:username"`
@@ -9,5 +12,9 @@ type User struct {
 	Name     string    `gorm:"not null;column:name"`
 	Birthday time.Time `gorm:"not null;column:birthday"`
 	Email    string    `gorm:"not null;column:email"`
+	// PlatformRole is granted from the command line only, see main.go.
+	PlatformRole  string     `gorm:"not null;default:'';column:platform_role"`
+	SuspendedAt   *time.Time `gorm:"column:suspend_time"`
+	SuspendReason string     `gorm:"not null;default:'';column:suspend_reason"`
 	Base
 }
@@ -64,7 +64,7 @@ func (a *applicationRepositoryImpl) FetchApplicationsByUser(ctx context.Context,
 				apply_records.room_id as room_id,
 				rooms."name" as room_name, 
 				rooms.admin_user_id as admin_user_id, 
-				rooms.user_ids as user_ids,
+				array(select room_members.user_id from room_members where room_members.room_id = rooms.id) as user_ids,
 				rooms.description`).
 		Joins(`inner join rooms on apply_records.room_id = rooms."id"`).
 		Where(`apply_records.user_id=?`, userID).
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	AddAuditLog(ctx context.Context, log *model.AuditLog) error
+	FetchAuditLogs(ctx context.Context, roomID uint64, actorUserID uint64, targetUserID uint64, action string, skip int, pageSize int) ([]*model.AuditLog, error)
+}
+
+type auditLogRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var auditLog AuditLogRepository
+
+func init() {
+	auditLog = &auditLogRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetAuditLogRepository() AuditLogRepository {
+	return auditLog
+}
+
+// AddAuditLog has to be called with the transaction of the decision, so both commit together.
+func (a *auditLogRepositoryImpl) AddAuditLog(ctx context.Context, log *model.AuditLog) error {
+	tx := GetTxContext(ctx, a.DB)
+	return tx.Create(log).Error
+}
+
+// FetchAuditLogs lists the newest entries first, zero ids and an empty action match everything.
+func (a *auditLogRepositoryImpl) FetchAuditLogs(ctx context.Context, roomID uint64, actorUserID uint64, targetUserID uint64, action string, skip int, pageSize int) ([]*model.AuditLog, error) {
+	tx := GetTxContext(ctx, a.DB)
+	logs := []*model.AuditLog{}
+	if roomID != 0 {
+		tx = tx.Where("room_id=?", roomID)
+	}
+	if actorUserID != 0 {
+		tx = tx.Where("actor_user_id=?", actorUserID)
+	}
+	if targetUserID != 0 {
+		tx = tx.Where("target_user_id=?", targetUserID)
+	}
+	if action != "" {
+		tx = tx.Where("action=?", action)
+	}
+	result := tx.Order("id DESC").Offset(skip).Limit(pageSize).Find(&logs)
+	return logs, result.Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type BlockRepository interface {
+	BlockUser(ctx context.Context, userID uint64, blockedUserID uint64) (created bool, err error)
+	UnblockUser(ctx context.Context, userID uint64, blockedUserID uint64) (ok bool, err error)
+	CheckBlocked(ctx context.Context, userID uint64, blockedUserID uint64) (blocked bool, err error)
+	GetBlockedUserIDs(ctx context.Context, userID uint64) ([]uint64, error)
+	FetchBlockedUsers(ctx context.Context, userID uint64, skip int, pageSize int) ([]*model.User, error)
+}
+
+type blockRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var block BlockRepository
+
+func init() {
+	block = &blockRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetBlockRepository() BlockRepository {
+	return block
+}
+
+func (b *blockRepositoryImpl) BlockUser(ctx context.Context, userID uint64, blockedUserID uint64) (bool, error) {
+	tx := GetTxContext(ctx, b.DB)
+	record := model.UserBlock{UserID: userID, BlockedUserID: blockedUserID}
+	result := tx.Where("user_id=? and blocked_user_id=?", userID, blockedUserID).FirstOrCreate(&record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (b *blockRepositoryImpl) UnblockUser(ctx context.Context, userID uint64, blockedUserID uint64) (bool, error) {
+	tx := GetTxContext(ctx, b.DB)
+	// hard delete, otherwise the unique index blocks the next BlockUser of the same pair
+	result := tx.Unscoped().Where("user_id=? and blocked_user_id=?", userID, blockedUserID).Delete(&model.UserBlock{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (b *blockRepositoryImpl) CheckBlocked(ctx context.Context, userID uint64, blockedUserID uint64) (bool, error) {
+	tx := GetTxContext(ctx, b.DB)
+	result := tx.Select("id").Where("user_id=? and blocked_user_id=?", userID, blockedUserID).First(&model.UserBlock{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, result.Error
+	}
+	return true, nil
+}
+
+func (b *blockRepositoryImpl) GetBlockedUserIDs(ctx context.Context, userID uint64) ([]uint64, error) {
+	tx := GetTxContext(ctx, b.DB)
+	var ids []uint64
+	result := tx.Model(&model.UserBlock{}).Where("user_id=?", userID).Pluck("blocked_user_id", &ids)
+	return ids, result.Error
+}
+
+func (b *blockRepositoryImpl) FetchBlockedUsers(ctx context.Context, userID uint64, skip int, pageSize int) ([]*model.User, error) {
+	tx := GetTxContext(ctx, b.DB)
+	users := []*model.User{}
+	result := tx.Table("user_blocks").
+		Select(`users.id as id, users.username as username, users.name as name, users.email as email`).
+		Joins(`inner join users on user_blocks.blocked_user_id = users.id`).
+		Where(`user_blocks.user_id=? and user_blocks.delete_time is null`, userID).
+		Order("user_blocks.id DESC").Offset(skip).Limit(pageSize).
+		Scan(&users)
+	return users, result.Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	SaveBookmark(ctx context.Context, bookmark *model.Bookmark) error
+	GetBookmark(ctx context.Context, userID uint64, bookmarkID uint64) (*model.Bookmark, error)
+	UpdateBookmark(ctx context.Context, userID uint64, bookmarkID uint64, settings map[string]interface{}) (ok bool, err error)
+	DeleteBookmark(ctx context.Context, userID uint64, bookmarkID uint64) (ok bool, err error)
+	FetchBookmarks(ctx context.Context, userID uint64, folder *string, cursor uint64, limit int) ([]*model.BookmarkRecord, error)
+	FetchFolders(ctx context.Context, userID uint64) ([]*model.BookmarkFolderRecord, error)
+}
+
+type bookmarkRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var bookmark BookmarkRepository
+
+func init() {
+	bookmark = &bookmarkRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetBookmarkRepository() BookmarkRepository {
+	return bookmark
+}
+
+// SaveBookmark bookmarks a message, saving the same message again replaces its note and folder.
+func (b *bookmarkRepositoryImpl) SaveBookmark(ctx context.Context, bookmark *model.Bookmark) error {
+	tx := GetTxContext(ctx, b.DB)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"note", "folder", "update_time"}),
+	}).Create(bookmark).Error
+}
+
+func (b *bookmarkRepositoryImpl) GetBookmark(ctx context.Context, userID uint64, bookmarkID uint64) (*model.Bookmark, error) {
+	tx := GetTxContext(ctx, b.DB)
+	bookmark := model.Bookmark{}
+	result := tx.Where("id=? and user_id=?", bookmarkID, userID).First(&bookmark)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &bookmark, nil
+}
+
+func (b *bookmarkRepositoryImpl) UpdateBookmark(ctx context.Context, userID uint64, bookmarkID uint64, settings map[string]interface{}) (bool, error) {
+	tx := GetTxContext(ctx, b.DB)
+	result := tx.Model(&model.Bookmark{}).Where("id=? and user_id=?", bookmarkID, userID).Updates(settings)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (b *bookmarkRepositoryImpl) DeleteBookmark(ctx context.Context, userID uint64, bookmarkID uint64) (bool, error) {
+	tx := GetTxContext(ctx, b.DB)
+	result := tx.Where("id=? and user_id=?", bookmarkID, userID).Delete(&model.Bookmark{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FetchBookmarks pages through the bookmarks of a user newest first, starting below the cursor id
+// when it is not zero. Bookmarks of rooms the user is no longer in are left out, the row stays and
+// shows up again after rejoining. A nil folder lists every folder.
+func (b *bookmarkRepositoryImpl) FetchBookmarks(ctx context.Context, userID uint64, folder *string, cursor uint64, limit int) ([]*model.BookmarkRecord, error) {
+	tx := GetTxContext(ctx, b.DB)
+	records := []*model.BookmarkRecord{}
+	tx = tx.Table("bookmarks").
+		Select("bookmarks.id, bookmarks.message_id, bookmarks.room_id, rooms.name as room_name, "+
+			"bookmarks.note, bookmarks.folder, bookmarks.create_time as created_at, "+
+			"messages.user_id as message_user_id, messages.kind as message_kind, "+
+			"messages.content as message_content, messages.create_time as message_created_at").
+		Joins("JOIN room_members ON room_members.room_id = bookmarks.room_id and room_members.user_id = bookmarks.user_id").
+		Joins("JOIN rooms ON rooms.id = bookmarks.room_id and rooms.delete_time is null").
+		Joins("LEFT JOIN messages ON messages.id = bookmarks.message_id and messages.delete_time is null").
+		Where("bookmarks.user_id=?", userID)
+	if folder != nil {
+		tx = tx.Where("bookmarks.folder=?", *folder)
+	}
+	if cursor != 0 {
+		tx = tx.Where("bookmarks.id < ?", cursor)
+	}
+	result := tx.Order("bookmarks.id DESC").Limit(limit).Scan(&records)
+	return records, result.Error
+}
+
+// FetchFolders counts the bookmarks FetchBookmarks would list in each folder.
+func (b *bookmarkRepositoryImpl) FetchFolders(ctx context.Context, userID uint64) ([]*model.BookmarkFolderRecord, error) {
+	tx := GetTxContext(ctx, b.DB)
+	records := []*model.BookmarkFolderRecord{}
+	result := tx.Table("bookmarks").Select("bookmarks.folder, count(*) as count").
+		Joins("JOIN room_members ON room_members.room_id = bookmarks.room_id and room_members.user_id = bookmarks.user_id").
+		Joins("JOIN rooms ON rooms.id = bookmarks.room_id and rooms.delete_time is null").
+		Where("bookmarks.user_id=?", userID).Group("bookmarks.folder").Order("bookmarks.folder").Scan(&records)
+	return records, result.Error
+}
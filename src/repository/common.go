@@ -3,6 +3,7 @@ package repository
 import (
 	"ChatRoomAPI/src"
 	"context"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +17,15 @@ func SetTxContext(ctx context.Context) (context.Context, *gorm.DB) {
 	return context.WithValue(ctx, tk, tx), tx
 }
 
+// RollbackOnPanic is deferred right after SetTxContext so a panic further down does not leave the
+// transaction open, the panic is passed on to the recovery middleware.
+func RollbackOnPanic(tx *gorm.DB) {
+	if r := recover(); r != nil {
+		tx.Rollback()
+		panic(r)
+	}
+}
+
 func GetTxContext(ctx context.Context, defaultTx *gorm.DB) *gorm.DB {
 	tx, ok := ctx.Value(tk).(*gorm.DB)
 	if !ok {
@@ -24,3 +34,10 @@ func GetTxContext(ctx context.Context, defaultTx *gorm.DB) *gorm.DB {
 	return tx.Session(&gorm.Session{NewDB: true})
 	// .Session(&gorm.Session{NewDB: true}) 避免查詢條件汙染
 }
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes user input match literally inside an ilike pattern.
+func escapeLike(keyword string) string {
+	return likeEscaper.Replace(keyword)
+}
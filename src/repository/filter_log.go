@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+const defaultMessageMaxLinks = 5
+
+// FilterWords is the server wide word list of the content filter, rooms add their own on top.
+func FilterWords() []string {
+	return src.GlobalConfig.YamlConfig.Filter.Words
+}
+
+// MessageMaxLinks is the number of links a message may hold in rooms that do not set their own.
+func MessageMaxLinks() int {
+	limit := src.GlobalConfig.YamlConfig.Filter.MaxLinks
+	if limit <= 0 {
+		limit = defaultMessageMaxLinks
+	}
+	return limit
+}
+
+type FilterLogRepository interface {
+	AddFilterLog(ctx context.Context, log *model.FilterLog) error
+	FetchFilterLogs(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.FilterLog, error)
+}
+
+type filterLogRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var filterLog FilterLogRepository
+
+func init() {
+	filterLog = &filterLogRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetFilterLogRepository() FilterLogRepository {
+	return filterLog
+}
+
+func (f *filterLogRepositoryImpl) AddFilterLog(ctx context.Context, log *model.FilterLog) error {
+	tx := GetTxContext(ctx, f.DB)
+	return tx.Create(log).Error
+}
+
+// FetchFilterLogs returns the decisions of a room, newest first.
+func (f *filterLogRepositoryImpl) FetchFilterLogs(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.FilterLog, error) {
+	tx := GetTxContext(ctx, f.DB)
+	logs := []*model.FilterLog{}
+	result := tx.Where("room_id=?", roomID).Order("id DESC").Offset(skip).Limit(pageSize).Find(&logs)
+	return logs, result.Error
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importStatusColumns are loaded when the archive itself is not needed.
+var importStatusColumns = []string{
+	"id", "source", "requested_by_user_id", "status", "error", "room_count", "message_count",
+	"finish_time", "create_time", "update_time",
+}
+
+// importStaleAfter hands a running import back to the queue when its worker died.
+const importStaleAfter = time.Hour
+
+type ImportRepository interface {
+	CreateImportJob(ctx context.Context, job *model.ImportJob) error
+	GetImportJob(ctx context.Context, jobID uint64) (*model.ImportJob, error)
+	ClaimPendingImport(ctx context.Context) (*model.ImportJob, error)
+	FinishImport(ctx context.Context, jobID uint64, roomCount int, messageCount int64) error
+	FailImport(ctx context.Context, jobID uint64, reason string) error
+
+	FindMapping(ctx context.Context, ownerUserID uint64, source string, kind string, externalID string) (localID uint64, found bool, err error)
+	SaveMapping(ctx context.Context, mapping *model.ImportMapping) error
+}
+
+type importRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var importRepo ImportRepository
+
+func init() {
+	importRepo = &importRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetImportRepository() ImportRepository {
+	return importRepo
+}
+
+func (r *importRepositoryImpl) CreateImportJob(ctx context.Context, job *model.ImportJob) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Create(job).Error
+}
+
+func (r *importRepositoryImpl) GetImportJob(ctx context.Context, jobID uint64) (*model.ImportJob, error) {
+	tx := GetTxContext(ctx, r.DB)
+	job := model.ImportJob{}
+	result := tx.Select(importStatusColumns).Where("id=?", jobID).First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// ClaimPendingImport marks the oldest waiting import as running and returns it with its archive,
+// nil when there is none. Imports locked by another worker are skipped.
+func (r *importRepositoryImpl) ClaimPendingImport(ctx context.Context) (*model.ImportJob, error) {
+	tx := GetTxContext(ctx, r.DB)
+	job := model.ImportJob{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status=? or (status=? and update_time < ?)", model.ImportStatusPending, model.ImportStatusRunning, time.Now().Add(-importStaleAfter)).
+		Order("id").First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	result = tx.Model(&job).Update("status", model.ImportStatusRunning)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// FinishImport drops the archive, it is not needed once every row is in.
+func (r *importRepositoryImpl) FinishImport(ctx context.Context, jobID uint64, roomCount int, messageCount int64) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Model(&model.ImportJob{}).Where("id=?", jobID).Updates(map[string]interface{}{
+		"status":        model.ImportStatusDone,
+		"archive":       nil,
+		"room_count":    roomCount,
+		"message_count": messageCount,
+		"finish_time":   time.Now(),
+	}).Error
+}
+
+func (r *importRepositoryImpl) FailImport(ctx context.Context, jobID uint64, reason string) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Model(&model.ImportJob{}).Where("id=?", jobID).Updates(map[string]interface{}{
+		"status":      model.ImportStatusFailed,
+		"error":       reason,
+		"finish_time": time.Now(),
+	}).Error
+}
+
+func (r *importRepositoryImpl) FindMapping(ctx context.Context, ownerUserID uint64, source string, kind string, externalID string) (uint64, bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	mapping := model.ImportMapping{}
+	result := tx.Select("local_id").
+		Where("owner_user_id=? and source=? and kind=? and external_id=?", ownerUserID, source, kind, externalID).
+		First(&mapping)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, result.Error
+	}
+	return mapping.LocalID, true, nil
+}
+
+func (r *importRepositoryImpl) SaveMapping(ctx context.Context, mapping *model.ImportMapping) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(mapping).Error
+}
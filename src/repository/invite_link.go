@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InviteLinkRepository interface {
+	CreateInviteLink(ctx context.Context, link *model.InviteLink) error
+	ConsumeInviteLink(ctx context.Context, token string) (*model.InviteLink, error)
+	FetchActiveInviteLinks(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.InviteLink, error)
+	RevokeInviteLink(ctx context.Context, roomID uint64, linkID uint64) (ok bool, err error)
+	InviteLinkExist(ctx context.Context, roomID uint64, linkID uint64) (bool, error)
+	RecordInviteLinkUse(ctx context.Context, linkID uint64, userID uint64, pending bool) error
+	SettleInviteLinkUse(ctx context.Context, roomID uint64, userID uint64, approved bool) error
+	FetchInviteLinkUses(ctx context.Context, linkID uint64, skip int, pageSize int) ([]*model.InviteLinkUseRecord, error)
+}
+
+type inviteLinkRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var inviteLink InviteLinkRepository
+
+func init() {
+	inviteLink = &inviteLinkRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetInviteLinkRepository() InviteLinkRepository {
+	return inviteLink
+}
+
+// activeInviteLink restricts a query to links that are neither revoked, expired nor used up.
+func activeInviteLink(db *gorm.DB) *gorm.DB {
+	return db.Where("revoke_time is null").
+		Where("expire_time is null or expire_time > ?", time.Now()).
+		Where("max_uses = 0 or use_count < max_uses")
+}
+
+func (i *inviteLinkRepositoryImpl) CreateInviteLink(ctx context.Context, link *model.InviteLink) error {
+	tx := GetTxContext(ctx, i.DB)
+	return tx.Create(link).Error
+}
+
+// ConsumeInviteLink increments use_count of a still valid link in a single statement, so concurrent
+// joins can never exceed max_uses. It returns nil when the token is unknown or no longer valid.
+func (i *inviteLinkRepositoryImpl) ConsumeInviteLink(ctx context.Context, token string) (*model.InviteLink, error) {
+	tx := GetTxContext(ctx, i.DB)
+	links := []*model.InviteLink{}
+	result := tx.Model(&links).Clauses(clause.Returning{}).
+		Scopes(activeInviteLink).Where("token=?", token).
+		Update("use_count", gorm.Expr("use_count + 1"))
+	if result.Error != nil {
+		return nil, result.Error
+	} else if len(links) == 0 {
+		return nil, nil
+	}
+	return links[0], nil
+}
+
+func (i *inviteLinkRepositoryImpl) FetchActiveInviteLinks(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.InviteLink, error) {
+	tx := GetTxContext(ctx, i.DB)
+	links := []*model.InviteLink{}
+	result := tx.Scopes(activeInviteLink).Where("room_id=?", roomID).
+		Order("id DESC").Offset(skip).Limit(pageSize).Find(&links)
+	return links, result.Error
+}
+
+func (i *inviteLinkRepositoryImpl) RevokeInviteLink(ctx context.Context, roomID uint64, linkID uint64) (bool, error) {
+	tx := GetTxContext(ctx, i.DB)
+	result := tx.Model(&model.InviteLink{}).Where("id=? and room_id=? and revoke_time is null", linkID, roomID).
+		Update("revoke_time", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (i *inviteLinkRepositoryImpl) InviteLinkExist(ctx context.Context, roomID uint64, linkID uint64) (bool, error) {
+	tx := GetTxContext(ctx, i.DB)
+	result := tx.Select("id").Where("id=? and room_id=?", linkID, roomID).First(&model.InviteLink{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, result.Error
+	}
+	return true, nil
+}
+
+func (i *inviteLinkRepositoryImpl) RecordInviteLinkUse(ctx context.Context, linkID uint64, userID uint64, pending bool) error {
+	tx := GetTxContext(ctx, i.DB)
+	use := model.InviteLinkUse{InviteLinkID: linkID, UserID: userID, Pending: pending}
+	return tx.Create(&use).Error
+}
+
+// SettleInviteLinkUse ends the pending use a user made of a link of the room when their application
+// is decided. An approved use stays on the link, a rejected one is removed and gives its use back.
+func (i *inviteLinkRepositoryImpl) SettleInviteLinkUse(ctx context.Context, roomID uint64, userID uint64, approved bool) error {
+	tx := GetTxContext(ctx, i.DB)
+	if approved {
+		return tx.Model(&model.InviteLinkUse{}).
+			Where("pending and user_id=? and invite_link_id in (select id from invite_links where room_id=?)", userID, roomID).
+			Update("pending", false).Error
+	}
+	return tx.Exec(`
+		WITH rejected AS (
+			DELETE FROM invite_link_uses
+			WHERE pending AND user_id = ? AND invite_link_id IN (SELECT id FROM invite_links WHERE room_id = ?)
+			RETURNING invite_link_id
+		)
+		UPDATE invite_links SET use_count = use_count - 1, update_time = now()
+		WHERE id IN (SELECT invite_link_id FROM rejected) AND use_count > 0`,
+		userID, roomID,
+	).Error
+}
+
+func (i *inviteLinkRepositoryImpl) FetchInviteLinkUses(ctx context.Context, linkID uint64, skip int, pageSize int) ([]*model.InviteLinkUseRecord, error) {
+	tx := GetTxContext(ctx, i.DB)
+	records := []*model.InviteLinkUseRecord{}
+	result := tx.Table("invite_link_uses").
+		Select(`invite_link_uses."id" as id,
+				invite_link_uses.user_id as user_id,
+				users."name" as name,
+				users."email" as email,
+				invite_link_uses.pending as pending,
+				invite_link_uses.create_time as create_time`).
+		Joins(`inner join users on invite_link_uses.user_id = users."id"`).
+		Where(`invite_link_uses.invite_link_id=?`, linkID).
+		Order("id DESC").Offset(skip).Limit(pageSize).
+		Scan(&records)
+	return records, result.Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LinkPreviewRepository interface {
+	SavePreviews(ctx context.Context, previews []*model.LinkPreview) error
+	FetchPreviews(ctx context.Context, messageIDs []uint64) ([]*model.LinkPreview, error)
+}
+
+type linkPreviewRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var linkPreview LinkPreviewRepository
+
+func init() {
+	linkPreview = &linkPreviewRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetLinkPreviewRepository() LinkPreviewRepository {
+	return linkPreview
+}
+
+// SavePreviews skips the previews a message already has.
+func (l *linkPreviewRepositoryImpl) SavePreviews(ctx context.Context, previews []*model.LinkPreview) error {
+	if len(previews) == 0 {
+		return nil
+	}
+	tx := GetTxContext(ctx, l.DB)
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(previews).Error
+}
+
+func (l *linkPreviewRepositoryImpl) FetchPreviews(ctx context.Context, messageIDs []uint64) ([]*model.LinkPreview, error) {
+	previews := []*model.LinkPreview{}
+	if len(messageIDs) == 0 {
+		return previews, nil
+	}
+	tx := GetTxContext(ctx, l.DB)
+	result := tx.Where("message_id in ?", messageIDs).Order("message_id, id").Find(&previews)
+	return previews, result.Error
+}
@@ -4,15 +4,26 @@ import (
 	"ChatRoomAPI/src"
 	"ChatRoomAPI/src/model"
 	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MessageRepository interface {
-	AddMessage(ctx context.Context, roomID uint64, userID uint64, content string) (*model.Message, error)
+	AddMessage(ctx context.Context, roomID uint64, userID uint64, content string, payload string) (*model.Message, error)
+	AddSystemMessage(ctx context.Context, roomID uint64, actorUserID uint64, content string, payload string) (*model.Message, error)
+	AddPollMessage(ctx context.Context, roomID uint64, userID uint64, question string, payload string) (*model.Message, error)
+	GetMessage(ctx context.Context, roomID uint64, messageID uint64) (*model.Message, error)
+	UserPosted(ctx context.Context, roomID uint64, userID uint64) (bool, error)
+	LockMessage(ctx context.Context, roomID uint64, messageID uint64) (exist bool, err error)
+	DeleteMessage(ctx context.Context, roomID uint64, messageID uint64) (ok bool, err error)
+	DeleteMessagesBefore(ctx context.Context, roomID uint64, before time.Time, limit int) (deleted int64, err error)
+	ImportMessages(ctx context.Context, messages []*model.Message) (inserted int64, err error)
 	FetchMessages(ctx context.Context, roomID uint64, TimeCursor time.Time,
 		resultMaxSize int32) (messages []*model.Message, NextTimeCursor time.Time, err error)
+	FetchMessagesAfter(ctx context.Context, roomID uint64, afterTime time.Time, afterID uint64, limit int) ([]*model.Message, error)
 }
 
 type messageRepositoryImpl struct {
@@ -29,9 +40,10 @@ func GetMessageRepository() MessageRepository {
 	return message
 }
 
-func (m *messageRepositoryImpl) AddMessage(ctx context.Context, roomID uint64, userID uint64, content string) (*model.Message, error) {
+// AddMessage writes a user message, payload is empty unless the message forwards or quotes another.
+func (m *messageRepositoryImpl) AddMessage(ctx context.Context, roomID uint64, userID uint64, content string, payload string) (*model.Message, error) {
 	tx := GetTxContext(ctx, m.DB)
-	message := model.Message{RoomID: roomID, UserID: userID, Content: content}
+	message := model.Message{RoomID: roomID, UserID: userID, Kind: model.MessageKindUser, Content: content, Payload: payload}
 	result := tx.Create(&message)
 	if result.Error != nil {
 		return nil, result.Error
@@ -39,6 +51,116 @@ func (m *messageRepositoryImpl) AddMessage(ctx context.Context, roomID uint64, u
 	return &message, nil
 }
 
+func (m *messageRepositoryImpl) AddSystemMessage(ctx context.Context, roomID uint64, actorUserID uint64, content string, payload string) (*model.Message, error) {
+	tx := GetTxContext(ctx, m.DB)
+	message := model.Message{RoomID: roomID, UserID: actorUserID, Kind: model.MessageKindSystem, Content: content, Payload: payload}
+	result := tx.Create(&message)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &message, nil
+}
+
+func (m *messageRepositoryImpl) AddPollMessage(ctx context.Context, roomID uint64, userID uint64, question string, payload string) (*model.Message, error) {
+	tx := GetTxContext(ctx, m.DB)
+	message := model.Message{RoomID: roomID, UserID: userID, Kind: model.MessageKindPoll, Content: question, Payload: payload}
+	result := tx.Create(&message)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &message, nil
+}
+
+func (m *messageRepositoryImpl) GetMessage(ctx context.Context, roomID uint64, messageID uint64) (*model.Message, error) {
+	tx := GetTxContext(ctx, m.DB)
+	message := model.Message{}
+	result := tx.Where("id=? and room_id=?", messageID, roomID).First(&message)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &message, nil
+}
+
+// UserPosted reports whether the user wrote a message or a poll in the room, deleted ones count.
+func (m *messageRepositoryImpl) UserPosted(ctx context.Context, roomID uint64, userID uint64) (bool, error) {
+	tx := GetTxContext(ctx, m.DB)
+	var count int64
+	result := tx.Unscoped().Model(&model.Message{}).
+		Where("room_id=? and user_id=? and kind<>?", roomID, userID, model.MessageKindSystem).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// LockMessage holds the message row until the surrounding transaction ends. It writes the row
+// rather than SELECT ... FOR UPDATE: a transaction queued behind a plain row lock goes on with its
+// REPEATABLE READ snapshot and can not see what the holder committed, after a write Postgres fails
+// it with a serialization error instead.
+func (m *messageRepositoryImpl) LockMessage(ctx context.Context, roomID uint64, messageID uint64) (bool, error) {
+	tx := GetTxContext(ctx, m.DB)
+	result := tx.Exec("UPDATE messages SET update_time = update_time WHERE id=? and room_id=? and delete_time is null", messageID, roomID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *messageRepositoryImpl) DeleteMessage(ctx context.Context, roomID uint64, messageID uint64) (bool, error) {
+	tx := GetTxContext(ctx, m.DB)
+	result := tx.Where("id=? and room_id=?", messageID, roomID).Delete(&model.Message{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteMessagesBefore hard deletes at most limit messages of a room created before the given time,
+// together with their pins, poll votes and link previews. Rows locked by another transaction are skipped and picked up next time.
+func (m *messageRepositoryImpl) DeleteMessagesBefore(ctx context.Context, roomID uint64, before time.Time, limit int) (int64, error) {
+	tx := GetTxContext(ctx, m.DB)
+	result := tx.Exec(`WITH expired AS (
+		SELECT id FROM messages WHERE room_id = ? AND create_time < ? ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED
+	), pins AS (
+		DELETE FROM pinned_messages WHERE message_id IN (SELECT id FROM expired)
+	), votes AS (
+		DELETE FROM poll_votes WHERE message_id IN (SELECT id FROM expired)
+	), previews AS (
+		DELETE FROM link_previews WHERE message_id IN (SELECT id FROM expired)
+	)
+	DELETE FROM messages WHERE id IN (SELECT id FROM expired)`, roomID, before, limit)
+	return result.RowsAffected, result.Error
+}
+
+// ImportMessages bulk inserts messages that carry an ExternalID and their original CreatedAt,
+// messages imported before are skipped.
+func (m *messageRepositoryImpl) ImportMessages(ctx context.Context, messages []*model.Message) (int64, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+	tx := GetTxContext(ctx, m.DB)
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "room_id"}, {Name: "external_id"}},
+		DoNothing: true,
+	}).CreateInBatches(messages, 500)
+	return result.RowsAffected, result.Error
+}
+
+// FetchMessagesAfter pages through a room from the oldest message on. The cursor is the create time
+// and id of the last message of the previous page, so messages sharing a create time are not lost
+// at a page boundary.
+func (m *messageRepositoryImpl) FetchMessagesAfter(ctx context.Context, roomID uint64, afterTime time.Time, afterID uint64, limit int) ([]*model.Message, error) {
+	tx := GetTxContext(ctx, m.DB)
+	messages := []*model.Message{}
+	result := tx.Select("id", "room_id", "user_id", "kind", "content", "payload", "create_time").
+		Where("room_id=? and (create_time, id) > (?, ?)", roomID, afterTime, afterID).
+		Order("create_time ASC, id ASC").Limit(limit).Find(&messages)
+	return messages, result.Error
+}
+
 func (m *messageRepositoryImpl) FetchMessages(ctx context.Context, roomID uint64, TimeCursor time.Time,
 	resultMaxSize int32) (messages []*model.Message, NextTimeCursor time.Time, err error) {
 
@@ -47,8 +169,9 @@ func (m *messageRepositoryImpl) FetchMessages(ctx context.Context, roomID uint64
 	}
 
 	tx := GetTxContext(ctx, m.DB)
-	columns := []string{"id", "room_id", "user_id", "content", "create_time"}
+	columns := []string{"id", "room_id", "user_id", "kind", "content", "payload", "create_time"}
 	var result *gorm.DB
+	tx = tx.Where("room_id=?", roomID)
 	if resultMaxSize > 0 {
 		result = tx.Select(columns).Where("create_time > ?", TimeCursor).Order("create_time ASC").Limit(int(resultMaxSize)).Find(&messages)
 	} else {
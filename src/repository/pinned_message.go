@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PinnedMessageRepository interface {
+	PinMessage(ctx context.Context, pin *model.PinnedMessage) (created bool, err error)
+	UnpinMessage(ctx context.Context, roomID uint64, messageID uint64) (ok bool, err error)
+	CountPins(ctx context.Context, roomID uint64) (int64, error)
+	FetchPins(ctx context.Context, roomID uint64) ([]*model.PinnedMessageRecord, error)
+	DeleteMessagePins(ctx context.Context, messageID uint64) error
+}
+
+type pinnedMessageRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var pinnedMessage PinnedMessageRepository
+
+func init() {
+	pinnedMessage = &pinnedMessageRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetPinnedMessageRepository() PinnedMessageRepository {
+	return pinnedMessage
+}
+
+// PinMessage reports created false when the message is already pinned.
+func (r *pinnedMessageRepositoryImpl) PinMessage(ctx context.Context, pin *model.PinnedMessage) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(pin)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *pinnedMessageRepositoryImpl) UnpinMessage(ctx context.Context, roomID uint64, messageID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Where("room_id=? and message_id=?", roomID, messageID).Delete(&model.PinnedMessage{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *pinnedMessageRepositoryImpl) CountPins(ctx context.Context, roomID uint64) (int64, error) {
+	tx := GetTxContext(ctx, r.DB)
+	var count int64
+	result := tx.Model(&model.PinnedMessage{}).Where("room_id=?", roomID).Count(&count)
+	return count, result.Error
+}
+
+// FetchPins returns the pinned messages of a room in the order they were pinned.
+func (r *pinnedMessageRepositoryImpl) FetchPins(ctx context.Context, roomID uint64) ([]*model.PinnedMessageRecord, error) {
+	tx := GetTxContext(ctx, r.DB)
+	records := []*model.PinnedMessageRecord{}
+	result := tx.Table("pinned_messages").
+		Select("pinned_messages.message_id, messages.user_id, messages.kind, messages.content, "+
+			"pinned_messages.pinned_by_user_id, pinned_messages.create_time as pinned_at, messages.create_time as created_at").
+		Joins("JOIN messages ON messages.id = pinned_messages.message_id and messages.delete_time is null").
+		Where("pinned_messages.room_id=?", roomID).
+		Order("pinned_messages.create_time ASC").
+		Scan(&records)
+	return records, result.Error
+}
+
+func (r *pinnedMessageRepositoryImpl) DeleteMessagePins(ctx context.Context, messageID uint64) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Where("message_id=?", messageID).Delete(&model.PinnedMessage{}).Error
+}
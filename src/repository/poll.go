@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PollRepository interface {
+	ReplaceVotes(ctx context.Context, messageID uint64, userID uint64, options []int) error
+	FetchVotes(ctx context.Context, messageIDs []uint64) ([]*model.PollVote, error)
+}
+
+type pollRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var poll PollRepository
+
+func init() {
+	poll = &pollRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetPollRepository() PollRepository {
+	return poll
+}
+
+// ReplaceVotes drops the earlier vote of the user on a poll and stores the given options instead,
+// no options retracts the vote.
+func (p *pollRepositoryImpl) ReplaceVotes(ctx context.Context, messageID uint64, userID uint64, options []int) error {
+	tx := GetTxContext(ctx, p.DB)
+	result := tx.Where("message_id=? and user_id=?", messageID, userID).Delete(&model.PollVote{})
+	if result.Error != nil {
+		return result.Error
+	} else if len(options) == 0 {
+		return nil
+	}
+
+	votes := make([]model.PollVote, 0, len(options))
+	for _, option := range options {
+		votes = append(votes, model.PollVote{MessageID: messageID, UserID: userID, Option: option})
+	}
+	return tx.Create(&votes).Error
+}
+
+func (p *pollRepositoryImpl) FetchVotes(ctx context.Context, messageIDs []uint64) ([]*model.PollVote, error) {
+	votes := []*model.PollVote{}
+	if len(messageIDs) == 0 {
+		return votes, nil
+	}
+	tx := GetTxContext(ctx, p.DB)
+	result := tx.Where("message_id in ?", messageIDs).Order("message_id, option_index, id").Find(&votes)
+	return votes, result.Error
+}
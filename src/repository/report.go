@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *model.Report) error
+	GetReport(ctx context.Context, reportID uint64) (*model.Report, error)
+	FindPendingReport(ctx context.Context, reporterID uint64, roomID uint64, targetUserID uint64, messageID uint64) (*model.Report, error)
+	FetchReports(ctx context.Context, roomID uint64, status string, skip int, pageSize int) ([]*model.Report, error)
+	FetchEscalatedReports(ctx context.Context, status string, skip int, pageSize int) ([]*model.Report, error)
+	ClaimReport(ctx context.Context, reportID uint64, userID uint64) (ok bool, err error)
+	CloseReport(ctx context.Context, reportID uint64, userID uint64, status string, action string, note string) (ok bool, err error)
+}
+
+type reportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var report ReportRepository
+
+func init() {
+	report = &reportRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetReportRepository() ReportRepository {
+	return report
+}
+
+var pendingReportStatuses = []string{model.ReportStatusOpen, model.ReportStatusClaimed}
+
+func (r *reportRepositoryImpl) CreateReport(ctx context.Context, report *model.Report) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Create(report).Error
+}
+
+func (r *reportRepositoryImpl) GetReport(ctx context.Context, reportID uint64) (*model.Report, error) {
+	tx := GetTxContext(ctx, r.DB)
+	report := model.Report{}
+	result := tx.Where("id=?", reportID).First(&report)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+// FindPendingReport returns the report the reporter already filed on the same user or message that
+// nobody decided on yet.
+func (r *reportRepositoryImpl) FindPendingReport(ctx context.Context, reporterID uint64, roomID uint64, targetUserID uint64, messageID uint64) (*model.Report, error) {
+	tx := GetTxContext(ctx, r.DB)
+	report := model.Report{}
+	result := tx.Where("reporter_id=? and room_id=? and target_user_id=? and message_id=? and status in ?",
+		reporterID, roomID, targetUserID, messageID, pendingReportStatuses).First(&report)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+// FetchReports lists the reports of a room with the given status, oldest first so the queue is
+// worked in the order it was filed.
+func (r *reportRepositoryImpl) FetchReports(ctx context.Context, roomID uint64, status string, skip int, pageSize int) ([]*model.Report, error) {
+	tx := GetTxContext(ctx, r.DB)
+	reports := []*model.Report{}
+	result := tx.Where("room_id=? and status=?", roomID, status).Order("id").Offset(skip).Limit(pageSize).Find(&reports)
+	return reports, result.Error
+}
+
+// FetchEscalatedReports is FetchReports across rooms for the reports sent to the platform operators.
+func (r *reportRepositoryImpl) FetchEscalatedReports(ctx context.Context, status string, skip int, pageSize int) ([]*model.Report, error) {
+	tx := GetTxContext(ctx, r.DB)
+	reports := []*model.Report{}
+	result := tx.Where("escalated = true and status=?", status).Order("id").Offset(skip).Limit(pageSize).Find(&reports)
+	return reports, result.Error
+}
+
+// ClaimReport hands an open report to userID, claiming a report userID already holds is a no-op.
+// ok is false when the report is closed or held by someone else.
+func (r *reportRepositoryImpl) ClaimReport(ctx context.Context, reportID uint64, userID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Report{}).
+		Where("id=? and (status=? or (status=? and claimed_by_user_id=?))", reportID, model.ReportStatusOpen, model.ReportStatusClaimed, userID).
+		Updates(map[string]interface{}{
+			"status":             model.ReportStatusClaimed,
+			"claimed_by_user_id": userID,
+			"claim_time":         gorm.Expr("coalesce(claim_time, ?)", time.Now()),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CloseReport resolves or dismisses a report that is open or claimed by userID.
+func (r *reportRepositoryImpl) CloseReport(ctx context.Context, reportID uint64, userID uint64, status string, action string, note string) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Report{}).
+		Where("id=? and (status=? or (status=? and claimed_by_user_id=?))", reportID, model.ReportStatusOpen, model.ReportStatusClaimed, userID).
+		Updates(map[string]interface{}{
+			"status":              status,
+			"resolved_by_user_id": userID,
+			"action":              action,
+			"note":                note,
+			"resolve_time":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -2,26 +2,76 @@ package repository
 
 import (
 	"ChatRoomAPI/src"
-	"ChatRoomAPI/src/common"
 	"ChatRoomAPI/src/model"
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// DirectRoomNamePrefix is reserved for direct message rooms, whose name is derived from the user pair.
+const DirectRoomNamePrefix = "dm::"
+
+// directRoomNameConflict targets idx_direct_room_name. The predicate has to be written out like the
+// index one, Postgres does not match a partial index against bound parameters.
+var directRoomNameConflict = clause.OnConflict{
+	Columns:     []clause.Column{{Name: "name"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "type = 1 and delete_time is null"}}},
+	DoNothing:   true,
+}
+
+const defaultDeletedRoomRetentionDay = 30
+
+// DeletedRoomRetention is how long a deleted room can still be restored before it is purged.
+func DeletedRoomRetention() time.Duration {
+	day := src.GlobalConfig.YamlConfig.Retention.DeletedRoomDay
+	if day <= 0 {
+		day = defaultDeletedRoomRetentionDay
+	}
+	return time.Duration(day) * 24 * time.Hour
+}
+
 type RoomRepository interface {
 	CreateRoom(ctx context.Context, adminUserID uint64, roomName string, description string) (room *model.Room, roomNameUsed bool, err error)
+	CreateDirectRoom(ctx context.Context, userID uint64, targetUserID uint64) (room *model.Room, created bool, err error)
 	RoomExist(ctx context.Context, roomID uint64) (bool, error)
+	LockRoom(ctx context.Context, roomID uint64) (exist bool, err error)
+	RoomArchived(ctx context.Context, roomID uint64) (archived bool, err error)
+	LockRoomForHold(ctx context.Context, roomID uint64) (*model.Room, error)
+	UpdateLegalHold(ctx context.Context, roomID uint64, legalHold bool) error
+	CommittedOccupancy(ctx context.Context, roomID uint64) (capacity uint32, members int64, err error)
+	ArchiveRoom(ctx context.Context, roomID uint64) (ok bool, err error)
+	UnarchiveRoom(ctx context.Context, roomID uint64) (ok bool, err error)
+	FetchDeletedRooms(ctx context.Context, adminUserID uint64, deletedAfter time.Time) ([]*model.Room, error)
+	ReadDeletedRoom(ctx context.Context, roomID uint64, deletedAfter time.Time) (*model.Room, error)
+	RestoreRoom(ctx context.Context, roomID uint64) (ok bool, err error)
+	FetchPurgeableRoomIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]uint64, error)
+	FetchRetentionRooms(ctx context.Context) ([]*model.Room, error)
+	PurgeRoom(ctx context.Context, roomID uint64) error
 	ReadRoomInfo(ctx context.Context, roomID uint64) (*model.Room, error)
 	GetAvailbleRooms(ctx context.Context, userID uint64, page int, pageSize int) ([]*model.Room, error)
 	DeleteRoom(ctx context.Context, roomID uint64, adminUserID uint64) (ok bool, err error)
+	UpdateJoinPolicy(ctx context.Context, roomID uint64, joinPolicy string, listed bool, tags []string) (ok bool, err error)
+	SearchListedRooms(ctx context.Context, keyword string, tags []string, skip int, pageSize int) ([]*model.RoomDirectoryRecord, error)
+	UpdateSlowMode(ctx context.Context, roomID uint64, second uint32) (ok bool, err error)
+	UpdateSettings(ctx context.Context, roomID uint64, settings map[string]interface{}) (ok bool, err error)
+	RoomNameUsed(ctx context.Context, roomName string, exceptRoomID uint64) (bool, error)
 
 	AddUser(ctx context.Context, roomID uint64, userID uint64) (ok bool, err error)
 	AdminChange(ctx context.Context, roomID uint64, adminUserID uint64, userID uint64) (ok bool, err error)
 	CheckUserInRoom(ctx context.Context, roomID uint64, userID uint64) (isUser bool, err error)
-	CheckAdminUserInRoom(ctx context.Context, roomID uint64, adminUserID uint64) (isAdmin bool, err error)
-	DeleteUser(ctx context.Context, roomID uint64, adminUserID uint64, userID uint64) (ok bool, err error)
+	DeleteUser(ctx context.Context, roomID uint64, userID uint64) (ok bool, err error)
+
+	GetMember(ctx context.Context, roomID uint64, userID uint64) (*model.RoomMember, error)
+	FetchMembers(ctx context.Context, roomID uint64) ([]*model.RoomMember, error)
+	UpdateNickname(ctx context.Context, roomID uint64, userID uint64, nickname string) (ok bool, err error)
+	UpdateRole(ctx context.Context, roomID uint64, userID uint64, role string) (ok bool, err error)
+	MuteUser(ctx context.Context, roomID uint64, userID uint64, mutedUntil *time.Time) (ok bool, err error)
+	ClearExpiredMutes(ctx context.Context, now time.Time) (cleared int64, err error)
 }
 
 type roomRepositoryImpl struct {
@@ -42,15 +92,59 @@ func (r *roomRepositoryImpl) CreateRoom(ctx context.Context, adminUserID uint64,
 	tx := GetTxContext(ctx, r.DB)
 	room := model.Room{
 		AdminUserID: adminUserID,
-		UserIDs:     common.UInt64ArrayToPQInt64Array([]uint64{adminUserID}),
 		Name:        roomName,
 		Description: description,
+		JoinPolicy:  model.JoinPolicyApproval,
 	}
 	result := tx.Where("name=?", roomName).FirstOrCreate(&room)
 	if result.Error != nil {
 		return nil, false, result.Error
+	} else if result.RowsAffected == 0 {
+		return &room, false, nil
+	}
+
+	member := model.RoomMember{RoomID: room.Id, UserID: adminUserID, Role: model.RoomRoleOwner}
+	if err := tx.Create(&member).Error; err != nil {
+		return nil, false, err
+	}
+	return &room, true, nil
+}
+
+func (r *roomRepositoryImpl) CreateDirectRoom(ctx context.Context, userID uint64, targetUserID uint64) (*model.Room, bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	low, high := userID, targetUserID
+	if low > high {
+		low, high = high, low
+	}
+	roomName := fmt.Sprintf("%s%d::%d", DirectRoomNamePrefix, low, high)
+	room := model.Room{
+		AdminUserID: 0,
+		Name:        roomName,
+		Description: "",
+		Type:        model.RoomTypeDirect,
+	}
+	// a concurrent call for the same pair makes the insert a no-op, or fails it with a serialization
+	// error when the other room was committed after this transaction began
+	result := tx.Clauses(directRoomNameConflict).Create(&room)
+	if result.Error != nil {
+		return nil, false, result.Error
+	} else if result.RowsAffected == 0 {
+		existing := model.Room{}
+		err := tx.Where("name=? and type=?", roomName, model.RoomTypeDirect).First(&existing).Error
+		if err != nil {
+			return nil, false, err
+		}
+		return &existing, false, nil
+	}
+
+	members := []*model.RoomMember{
+		{RoomID: room.Id, UserID: low, Role: model.RoomRoleMember},
+		{RoomID: room.Id, UserID: high, Role: model.RoomRoleMember},
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error; err != nil {
+		return nil, false, err
 	}
-	return &room, result.RowsAffected > 0, nil
+	return &room, true, nil
 }
 
 func (r *roomRepositoryImpl) RoomExist(ctx context.Context, roomID uint64) (bool, error) {
@@ -66,10 +160,191 @@ func (r *roomRepositoryImpl) RoomExist(ctx context.Context, roomID uint64) (bool
 	return true, nil
 }
 
+// LockRoom takes a row lock on the room until the surrounding transaction ends, it serializes
+// membership changes that depend on the current member list.
+func (r *roomRepositoryImpl) LockRoom(ctx context.Context, roomID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id=?", roomID).First(&model.Room{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, result.Error
+	}
+	return true, nil
+}
+
+// RoomArchived reports false for rooms that do not exist, callers check existence on their own.
+func (r *roomRepositoryImpl) RoomArchived(ctx context.Context, roomID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	var count int64
+	result := tx.Model(&model.Room{}).Where("id=? and archive_time is not null", roomID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// LockRoomForHold locks a room like LockRoom but also finds deleted rooms, a hold has to reach a
+// room waiting for the purge. Only id, legal_hold and delete_time are loaded, nil when there is none.
+func (r *roomRepositoryImpl) LockRoomForHold(ctx context.Context, roomID uint64) (*model.Room, error) {
+	tx := GetTxContext(ctx, r.DB)
+	room := model.Room{}
+	result := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "legal_hold", "delete_time").
+		Where("id=?", roomID).First(&room)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &room, nil
+}
+
+func (r *roomRepositoryImpl) UpdateLegalHold(ctx context.Context, roomID uint64, legalHold bool) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Unscoped().Model(&model.Room{}).Where("id=?", roomID).Update("legal_hold", legalHold).Error
+}
+
+// CommittedOccupancy reads the capacity and member count as committed right now, deliberately outside
+// the transaction in ctx. Transactions run under REPEATABLE READ, so a count inside one still sees
+// its snapshot and misses members that a join waited on by LockRoom has committed since.
+func (r *roomRepositoryImpl) CommittedOccupancy(ctx context.Context, roomID uint64) (uint32, int64, error) {
+	occupancy := struct {
+		Capacity uint32
+		Members  int64
+	}{}
+	result := r.DB.WithContext(ctx).Model(&model.Room{}).
+		Select("capacity, (SELECT count(*) FROM room_members WHERE room_members.room_id = rooms.id) AS members").
+		Where("id=?", roomID).Take(&occupancy)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, 0, nil
+		}
+		return 0, 0, result.Error
+	}
+	return occupancy.Capacity, occupancy.Members, nil
+}
+
+func (r *roomRepositoryImpl) ArchiveRoom(ctx context.Context, roomID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Room{}).Where("id=? and archive_time is null", roomID).Update("archive_time", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *roomRepositoryImpl) UnarchiveRoom(ctx context.Context, roomID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Room{}).Where("id=? and archive_time is not null", roomID).Update("archive_time", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FetchDeletedRooms lists soft deleted rooms of an owner that can still be restored.
+func (r *roomRepositoryImpl) FetchDeletedRooms(ctx context.Context, adminUserID uint64, deletedAfter time.Time) ([]*model.Room, error) {
+	tx := GetTxContext(ctx, r.DB)
+	rooms := []*model.Room{}
+	result := tx.Unscoped().Select("id", "name", "description", "delete_time").
+		Where("admin_user_id=? and delete_time > ?", adminUserID, deletedAfter).
+		Order("delete_time DESC").Find(&rooms)
+	return rooms, result.Error
+}
+
+func (r *roomRepositoryImpl) ReadDeletedRoom(ctx context.Context, roomID uint64, deletedAfter time.Time) (*model.Room, error) {
+	tx := GetTxContext(ctx, r.DB)
+	room := model.Room{}
+	result := tx.Unscoped().Select("id", "name", "admin_user_id", "delete_time").
+		Where("id=? and delete_time > ?", roomID, deletedAfter).First(&room)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &room, nil
+}
+
+func (r *roomRepositoryImpl) RestoreRoom(ctx context.Context, roomID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Unscoped().Model(&model.Room{}).Where("id=? and delete_time is not null", roomID).Update("delete_time", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FetchPurgeableRoomIDs lists rooms deleted before deletedBefore, rooms under legal hold are kept
+// until the hold is lifted.
+func (r *roomRepositoryImpl) FetchPurgeableRoomIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]uint64, error) {
+	tx := GetTxContext(ctx, r.DB)
+	roomIDs := []uint64{}
+	result := tx.Unscoped().Model(&model.Room{}).Where("delete_time <= ? and legal_hold = false", deletedBefore).
+		Order("delete_time ASC").Limit(limit).Pluck("id", &roomIDs)
+	return roomIDs, result.Error
+}
+
+// FetchRetentionRooms lists the rooms whose messages expire, rooms under legal hold are left out.
+func (r *roomRepositoryImpl) FetchRetentionRooms(ctx context.Context) ([]*model.Room, error) {
+	tx := GetTxContext(ctx, r.DB)
+	rooms := []*model.Room{}
+	result := tx.Select("id", "retention_day").Where("retention_day > 0 and legal_hold = false").Order("id").Find(&rooms)
+	return rooms, result.Error
+}
+
+// roomDependentTables are hard deleted together with a purged room, children before parents.
+// audit_logs is left alone, the trail has to outlive the room.
+var roomDependentTables = []struct {
+	table string
+	where string
+}{
+	{"invite_link_uses", "invite_link_id in (select id from invite_links where room_id = ?)"},
+	{"invite_links", "room_id = ?"},
+	{"invite_records", "room_id = ?"},
+	{"apply_records", "room_id = ?"},
+	{"room_bans", "room_id = ?"},
+	{"pinned_messages", "room_id = ?"},
+	{"poll_votes", "message_id in (select id from messages where room_id = ?)"},
+	{"link_previews", "message_id in (select id from messages where room_id = ?)"},
+	{"bookmarks", "room_id = ?"},
+	{"room_exports", "room_id = ?"},
+	{"filter_logs", "room_id = ?"},
+	{"reports", "room_id = ?"},
+	{"scheduled_jobs", "room_id = ?"},
+	// a later import of the same archive creates the room anew instead of resolving to a purged id
+	{"import_mappings", "kind = '" + model.ImportMappingRoom + "' and local_id = ?"},
+	{"messages", "room_id = ?"},
+	{"room_members", "room_id = ?"},
+}
+
+// PurgeRoom permanently removes a room and every row that belongs to it, it should run in a transaction.
+func (r *roomRepositoryImpl) PurgeRoom(ctx context.Context, roomID uint64) error {
+	tx := GetTxContext(ctx, r.DB)
+	for _, dependent := range roomDependentTables {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", dependent.table, dependent.where), roomID).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id=?", roomID).Delete(&model.Room{}).Error
+}
+
+// roomInfoColumns are the rooms columns loaded by ReadRoomInfo and GetAvailbleRooms.
+var roomInfoColumns = []string{
+	"id", "name", "admin_user_id", "description", "type", "topic", "avatar_url", "capacity",
+	"join_policy", "listed", "tags", "slow_mode_second", "archive_time",
+	"announcement", "announcement_user_id", "announcement_time", "mode", "retention_day", "legal_hold",
+	"filter_action", "filter_words", "max_links",
+}
+
 func (r *roomRepositoryImpl) ReadRoomInfo(ctx context.Context, roomID uint64) (*model.Room, error) {
 	tx := GetTxContext(ctx, r.DB)
 	roomInfo := model.Room{}
-	result := tx.Select("id", "name", "admin_user_id", "user_ids", "description").Where("id=?", roomID).First(&roomInfo)
+	result := tx.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("joined_at ASC")
+	}).Select(roomInfoColumns).Where("id=?", roomID).First(&roomInfo)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -82,10 +357,17 @@ func (r *roomRepositoryImpl) ReadRoomInfo(ctx context.Context, roomID uint64) (*
 func (r *roomRepositoryImpl) GetAvailbleRooms(ctx context.Context, userID uint64, page int, pageSize int) ([]*model.Room, error) {
 	tx := GetTxContext(ctx, r.DB)
 	roomsInfo := []*model.Room{}
-	result := tx.
-		Select("id", "name", "admin_user_id", "user_ids", "description").
-		Where("? = ANY (user_ids)", userID).
-		Order("id DESC").Offset(page).Limit(pageSize).Find(&roomsInfo)
+	columns := make([]string, len(roomInfoColumns))
+	for i, column := range roomInfoColumns {
+		columns[i] = "rooms." + column
+	}
+	result := tx.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("joined_at ASC")
+	}).
+		Select(columns).
+		Joins("inner join room_members on room_members.room_id = rooms.id").
+		Where("room_members.user_id = ?", userID).
+		Order("rooms.id DESC").Offset(page).Limit(pageSize).Find(&roomsInfo)
 	return roomsInfo, result.Error
 }
 
@@ -93,91 +375,180 @@ func (r *roomRepositoryImpl) DeleteRoom(ctx context.Context, roomID uint64, admi
 	tx := GetTxContext(ctx, r.DB)
 	result := tx.Where("id=? and admin_user_id=?", roomID, adminUserID).Delete(&model.Room{})
 	if result.Error != nil {
-		return false, err
+		return false, result.Error
 	} else {
-		return result.RowsAffected > 0, err
+		return result.RowsAffected > 0, nil
 	}
 }
 
-func (r *roomRepositoryImpl) AddUser(ctx context.Context, roomID uint64, userID uint64) (ok bool, err error) {
+func (r *roomRepositoryImpl) UpdateJoinPolicy(ctx context.Context, roomID uint64, joinPolicy string, listed bool, tags []string) (bool, error) {
 	tx := GetTxContext(ctx, r.DB)
-	var roomInfo model.Room
-	result := tx.Where("id=?", roomID).First(&roomInfo)
+	result := tx.Model(&model.Room{}).Where("id=? and type=?", roomID, model.RoomTypeGroup).
+		Updates(map[string]interface{}{"join_policy": joinPolicy, "listed": listed, "tags": pq.StringArray(tags)})
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return false, nil
-		}
 		return false, result.Error
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	result = tx.Model(&model.Room{}).Where("id=?", roomID).Update("user_ids", gorm.Expr("array_append(user_ids, ?)", int64(userID)))
+func (r *roomRepositoryImpl) UpdateSlowMode(ctx context.Context, roomID uint64, second uint32) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Room{}).Where("id=? and type=?", roomID, model.RoomTypeGroup).Update("slow_mode_second", second)
 	if result.Error != nil {
 		return false, result.Error
 	}
 	return result.RowsAffected > 0, nil
 }
 
-func (r *roomRepositoryImpl) AdminChange(ctx context.Context, roomID uint64, adminUserID uint64, userID uint64) (ok bool, err error) {
+func (r *roomRepositoryImpl) UpdateSettings(ctx context.Context, roomID uint64, settings map[string]interface{}) (bool, error) {
 	tx := GetTxContext(ctx, r.DB)
-	result := tx.Where("id=? and admin_user_id=?", roomID, adminUserID).Update("admin_user_id", userID)
+	result := tx.Model(&model.Room{}).Where("id=? and type=?", roomID, model.RoomTypeGroup).Updates(settings)
 	if result.Error != nil {
 		return false, result.Error
 	}
 	return result.RowsAffected > 0, nil
 }
 
-func (r *roomRepositoryImpl) CheckAdminUserInRoom(ctx context.Context, roomID uint64, adminUserID uint64) (isAdmin bool, err error) {
+func (r *roomRepositoryImpl) RoomNameUsed(ctx context.Context, roomName string, exceptRoomID uint64) (bool, error) {
 	tx := GetTxContext(ctx, r.DB)
-	result := tx.Select("id").Where("id=? and admin_user_id=?", roomID, adminUserID).First(&model.Room{})
+	var count int64
+	result := tx.Model(&model.Room{}).Where("name=? and id<>?", roomName, exceptRoomID).Count(&count)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return false, nil
-		}
 		return false, result.Error
 	}
-	return true, nil
+	return count > 0, nil
 }
 
-func (r *roomRepositoryImpl) CheckUserInRoom(ctx context.Context, roomID uint64, userID uint64) (isUser bool, err error) {
+// SearchListedRooms matches keyword against name and description and requires every given tag,
+// archived and direct rooms are never listed.
+func (r *roomRepositoryImpl) SearchListedRooms(ctx context.Context, keyword string, tags []string, skip int, pageSize int) ([]*model.RoomDirectoryRecord, error) {
+	tx := GetTxContext(ctx, r.DB)
+	records := []*model.RoomDirectoryRecord{}
+	query := tx.Table("rooms").
+		Select(`rooms."id" as id,
+				rooms."name" as name,
+				rooms.description as description,
+				rooms.join_policy as join_policy,
+				rooms.tags as tags,
+				(select count(*) from room_members where room_members.room_id = rooms.id) as member_count`).
+		Where("rooms.listed = true and rooms.type = ? and rooms.archive_time is null and rooms.delete_time is null", model.RoomTypeGroup)
+	if keyword != "" {
+		pattern := "%" + escapeLike(keyword) + "%"
+		query = query.Where("rooms.name ilike ? or rooms.description ilike ?", pattern, pattern)
+	}
+	if len(tags) > 0 {
+		query = query.Where("rooms.tags @> ?", pq.StringArray(tags))
+	}
+	result := query.Order("member_count DESC, id DESC").Offset(skip).Limit(pageSize).Scan(&records)
+	return records, result.Error
+}
+
+func (r *roomRepositoryImpl) AddUser(ctx context.Context, roomID uint64, userID uint64) (ok bool, err error) {
 	tx := GetTxContext(ctx, r.DB)
-	var roomInfo model.Room
-	result := tx.Select("id").Where("id=? and ?=ANY (user_ids)", roomID, userID).First(&roomInfo)
+	exist, err := r.RoomExist(ctx, roomID)
+	if err != nil || !exist {
+		return false, err
+	}
+
+	member := model.RoomMember{RoomID: roomID, UserID: userID, Role: model.RoomRoleMember}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return false, nil
-		}
 		return false, result.Error
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	for _, u := range roomInfo.UserIDs {
-		if uint64(u) == userID {
-			return true, nil
-		}
+func (r *roomRepositoryImpl) AdminChange(ctx context.Context, roomID uint64, adminUserID uint64, userID uint64) (ok bool, err error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.Room{}).Where("id=? and admin_user_id=?", roomID, adminUserID).Update("admin_user_id", userID)
+	if result.Error != nil {
+		return false, result.Error
+	} else if result.RowsAffected == 0 {
+		return false, nil
 	}
-	return true, nil
+
+	result = tx.Model(&model.RoomMember{}).Where("room_id=? and user_id=?", roomID, adminUserID).Update("role", model.RoomRoleMember)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	result = tx.Model(&model.RoomMember{}).Where("room_id=? and user_id=?", roomID, userID).Update("role", model.RoomRoleOwner)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
 
-func (r *roomRepositoryImpl) DeleteUser(ctx context.Context, roomID uint64, adminUserID uint64, userID uint64) (bool, error) {
+func (r *roomRepositoryImpl) CheckUserInRoom(ctx context.Context, roomID uint64, userID uint64) (isUser bool, err error) {
+	member, err := r.GetMember(ctx, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil, nil
+}
+
+func (r *roomRepositoryImpl) DeleteUser(ctx context.Context, roomID uint64, userID uint64) (bool, error) {
 	tx := GetTxContext(ctx, r.DB)
-	var roomInfo model.Room
-	result := tx.Where("id=?", roomID).First(&roomInfo)
+	result := tx.Where("room_id=? and user_id=?", roomID, userID).Delete(&model.RoomMember{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetMember returns nil for members of a deleted room too, membership is what grants access and
+// room_members rows are kept for a restore.
+func (r *roomRepositoryImpl) GetMember(ctx context.Context, roomID uint64, userID uint64) (*model.RoomMember, error) {
+	tx := GetTxContext(ctx, r.DB)
+	member := model.RoomMember{}
+	result := tx.Select("room_members.*").
+		Joins("inner join rooms on rooms.id = room_members.room_id and rooms.delete_time is null").
+		Where("room_members.room_id=? and room_members.user_id=?", roomID, userID).First(&member)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return false, nil
+			return nil, nil
 		}
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
+func (r *roomRepositoryImpl) FetchMembers(ctx context.Context, roomID uint64) ([]*model.RoomMember, error) {
+	tx := GetTxContext(ctx, r.DB)
+	members := []*model.RoomMember{}
+	result := tx.Where("room_id=?", roomID).Order("joined_at ASC").Find(&members)
+	return members, result.Error
+}
+
+func (r *roomRepositoryImpl) UpdateNickname(ctx context.Context, roomID uint64, userID uint64, nickname string) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.RoomMember{}).Where("room_id=? and user_id=?", roomID, userID).Update("nickname", nickname)
+	if result.Error != nil {
 		return false, result.Error
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	newUsers := []int64{}
-	for _, u := range roomInfo.UserIDs {
-		if u == int64(userID) {
-			continue
-		}
-		newUsers = append(newUsers, u)
+func (r *roomRepositoryImpl) UpdateRole(ctx context.Context, roomID uint64, userID uint64, role string) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.RoomMember{}).Where("room_id=? and user_id=?", roomID, userID).Update("role", role)
+	if result.Error != nil {
+		return false, result.Error
 	}
-	result = tx.Where("room=?", roomID).Update("user_ids", newUsers)
+	return result.RowsAffected > 0, nil
+}
+
+// MuteUser sets or, with a nil mutedUntil, lifts the mute of a member.
+func (r *roomRepositoryImpl) MuteUser(ctx context.Context, roomID uint64, userID uint64, mutedUntil *time.Time) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.RoomMember{}).Where("room_id=? and user_id=?", roomID, userID).Update("muted_until", mutedUntil)
 	if result.Error != nil {
 		return false, result.Error
 	}
 	return result.RowsAffected > 0, nil
 }
+
+func (r *roomRepositoryImpl) ClearExpiredMutes(ctx context.Context, now time.Time) (int64, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Model(&model.RoomMember{}).Where("muted_until <= ?", now).Update("muted_until", nil)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoomBanRepository interface {
+	BanUser(ctx context.Context, ban *model.RoomBan) error
+	UnbanUser(ctx context.Context, roomID uint64, userID uint64) (ok bool, err error)
+	CheckBanned(ctx context.Context, roomID uint64, userID uint64) (banned bool, err error)
+	FetchBans(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.RoomBan, error)
+	DeleteExpiredBans(ctx context.Context, now time.Time) (deleted int64, err error)
+}
+
+type roomBanRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var roomBan RoomBanRepository
+
+func init() {
+	roomBan = &roomBanRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetRoomBanRepository() RoomBanRepository {
+	return roomBan
+}
+
+// activeBan skips bans whose expiry has passed but which the cleanup job has not removed yet.
+func activeBan(db *gorm.DB) *gorm.DB {
+	return db.Where("expire_time is null or expire_time > ?", time.Now())
+}
+
+// BanUser creates the ban or replaces reason and expiry of an existing one.
+func (r *roomBanRepositoryImpl) BanUser(ctx context.Context, ban *model.RoomBan) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "room_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"banned_by_user_id", "reason", "expire_time", "update_time"}),
+	}).Create(ban).Error
+}
+
+func (r *roomBanRepositoryImpl) UnbanUser(ctx context.Context, roomID uint64, userID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Scopes(activeBan).Where("room_id=? and user_id=?", roomID, userID).Delete(&model.RoomBan{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *roomBanRepositoryImpl) CheckBanned(ctx context.Context, roomID uint64, userID uint64) (bool, error) {
+	tx := GetTxContext(ctx, r.DB)
+	var count int64
+	result := tx.Model(&model.RoomBan{}).Scopes(activeBan).Where("room_id=? and user_id=?", roomID, userID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (r *roomBanRepositoryImpl) FetchBans(ctx context.Context, roomID uint64, skip int, pageSize int) ([]*model.RoomBan, error) {
+	tx := GetTxContext(ctx, r.DB)
+	bans := []*model.RoomBan{}
+	result := tx.Scopes(activeBan).Where("room_id=?", roomID).Order("id DESC").Offset(skip).Limit(pageSize).Find(&bans)
+	return bans, result.Error
+}
+
+func (r *roomBanRepositoryImpl) DeleteExpiredBans(ctx context.Context, now time.Time) (int64, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Where("expire_time <= ?", now).Delete(&model.RoomBan{})
+	return result.RowsAffected, result.Error
+}
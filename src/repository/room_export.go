@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exportStatusColumns are loaded when the transcript itself is not needed.
+var exportStatusColumns = []string{
+	"id", "room_id", "requested_by_user_id", "format", "status", "error", "token", "message_count",
+	"expire_time", "finish_time", "create_time", "update_time",
+}
+
+// exportStaleAfter hands a running export back to the queue when its worker died.
+const exportStaleAfter = time.Hour
+
+type RoomExportRepository interface {
+	CreateExport(ctx context.Context, export *model.RoomExport) error
+	GetExport(ctx context.Context, exportID uint64) (*model.RoomExport, error)
+	GetExportByToken(ctx context.Context, token string) (*model.RoomExport, error)
+	ClaimPendingExport(ctx context.Context) (*model.RoomExport, error)
+	FinishExport(ctx context.Context, exportID uint64, content []byte, messageCount int, expiresAt time.Time) error
+	FailExport(ctx context.Context, exportID uint64, reason string) error
+	DeleteExpiredExports(ctx context.Context, now time.Time) (deleted int64, err error)
+}
+
+type roomExportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var roomExport RoomExportRepository
+
+func init() {
+	roomExport = &roomExportRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetRoomExportRepository() RoomExportRepository {
+	return roomExport
+}
+
+func (r *roomExportRepositoryImpl) CreateExport(ctx context.Context, export *model.RoomExport) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Create(export).Error
+}
+
+func (r *roomExportRepositoryImpl) GetExport(ctx context.Context, exportID uint64) (*model.RoomExport, error) {
+	tx := GetTxContext(ctx, r.DB)
+	export := model.RoomExport{}
+	result := tx.Select(exportStatusColumns).Where("id=?", exportID).First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+// GetExportByToken returns a finished export with its content, nil when the token is unknown,
+// the export is not done yet or its link expired.
+func (r *roomExportRepositoryImpl) GetExportByToken(ctx context.Context, token string) (*model.RoomExport, error) {
+	tx := GetTxContext(ctx, r.DB)
+	export := model.RoomExport{}
+	result := tx.Where("token=? and status=? and expire_time > ?", token, model.ExportStatusDone, time.Now()).First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+// ClaimPendingExport marks the oldest waiting export as running and returns it, nil when there is
+// none. Exports locked by another worker are skipped.
+func (r *roomExportRepositoryImpl) ClaimPendingExport(ctx context.Context) (*model.RoomExport, error) {
+	tx := GetTxContext(ctx, r.DB)
+	export := model.RoomExport{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Select(exportStatusColumns).
+		Where("status=? or (status=? and update_time < ?)", model.ExportStatusPending, model.ExportStatusRunning, time.Now().Add(-exportStaleAfter)).
+		Order("id").First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	result = tx.Model(&export).Update("status", model.ExportStatusRunning)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &export, nil
+}
+
+func (r *roomExportRepositoryImpl) FinishExport(ctx context.Context, exportID uint64, content []byte, messageCount int, expiresAt time.Time) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Model(&model.RoomExport{}).Where("id=?", exportID).Updates(map[string]interface{}{
+		"status":        model.ExportStatusDone,
+		"content":       content,
+		"message_count": messageCount,
+		"expire_time":   expiresAt,
+		"finish_time":   time.Now(),
+	}).Error
+}
+
+func (r *roomExportRepositoryImpl) FailExport(ctx context.Context, exportID uint64, reason string) error {
+	tx := GetTxContext(ctx, r.DB)
+	return tx.Model(&model.RoomExport{}).Where("id=?", exportID).Updates(map[string]interface{}{
+		"status":      model.ExportStatusFailed,
+		"error":       reason,
+		"finish_time": time.Now(),
+	}).Error
+}
+
+func (r *roomExportRepositoryImpl) DeleteExpiredExports(ctx context.Context, now time.Time) (int64, error) {
+	tx := GetTxContext(ctx, r.DB)
+	result := tx.Where("expire_time <= ?", now).Delete(&model.RoomExport{})
+	return result.RowsAffected, result.Error
+}
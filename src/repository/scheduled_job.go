@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ScheduledJobRepository interface {
+	CreateScheduledJob(ctx context.Context, job *model.ScheduledJob) error
+	GetScheduledJob(ctx context.Context, jobID uint64) (*model.ScheduledJob, error)
+	FetchScheduledJobs(ctx context.Context, userID uint64, kind string, status string) ([]*model.ScheduledJob, error)
+	UpdatePendingJob(ctx context.Context, jobID uint64, settings map[string]interface{}) (ok bool, err error)
+	ClaimDueJobs(ctx context.Context, now time.Time, limit int) ([]*model.ScheduledJob, error)
+	CancelUserPendingJobs(ctx context.Context, userID uint64) (cancelled int64, err error)
+	FinishScheduledJob(ctx context.Context, jobID uint64) error
+	FailScheduledJob(ctx context.Context, jobID uint64, reason string) error
+	FetchFiredReminders(ctx context.Context, userID uint64, limit int) ([]*model.ScheduledJob, error)
+	AckReminders(ctx context.Context, userID uint64, jobIDs []uint64) (acked int64, err error)
+}
+
+type scheduledJobRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+var scheduledJob ScheduledJobRepository
+
+func init() {
+	scheduledJob = &scheduledJobRepositoryImpl{DB: src.GlobalConfig.DB}
+}
+
+func GetScheduledJobRepository() ScheduledJobRepository {
+	return scheduledJob
+}
+
+func (s *scheduledJobRepositoryImpl) CreateScheduledJob(ctx context.Context, job *model.ScheduledJob) error {
+	tx := GetTxContext(ctx, s.DB)
+	return tx.Create(job).Error
+}
+
+func (s *scheduledJobRepositoryImpl) GetScheduledJob(ctx context.Context, jobID uint64) (*model.ScheduledJob, error) {
+	tx := GetTxContext(ctx, s.DB)
+	job := model.ScheduledJob{}
+	result := tx.Where("id=?", jobID).First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// FetchScheduledJobs lists the jobs of a user by run time, an empty kind matches both kinds.
+func (s *scheduledJobRepositoryImpl) FetchScheduledJobs(ctx context.Context, userID uint64, kind string, status string) ([]*model.ScheduledJob, error) {
+	tx := GetTxContext(ctx, s.DB)
+	jobs := []*model.ScheduledJob{}
+	tx = tx.Where("user_id=? and status=?", userID, status)
+	if kind != "" {
+		tx = tx.Where("kind=?", kind)
+	}
+	result := tx.Order("run_time, id").Find(&jobs)
+	return jobs, result.Error
+}
+
+// UpdatePendingJob only touches a job the worker has not claimed yet, ok is false otherwise.
+func (s *scheduledJobRepositoryImpl) UpdatePendingJob(ctx context.Context, jobID uint64, settings map[string]interface{}) (bool, error) {
+	tx := GetTxContext(ctx, s.DB)
+	result := tx.Model(&model.ScheduledJob{}).Where("id=? and status=?", jobID, model.ScheduledJobStatusPending).Updates(settings)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDueJobs marks at most limit pending jobs whose run time passed as running and returns them.
+// Jobs locked by another worker are skipped. A running job is never handed out again, so a message
+// is posted at most once even when a worker dies half way.
+func (s *scheduledJobRepositoryImpl) ClaimDueJobs(ctx context.Context, now time.Time, limit int) ([]*model.ScheduledJob, error) {
+	tx := GetTxContext(ctx, s.DB)
+	jobs := []*model.ScheduledJob{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status=? and run_time <= ?", model.ScheduledJobStatusPending, now).
+		Order("run_time, id").Limit(limit).Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	} else if len(jobs) == 0 {
+		return jobs, nil
+	}
+
+	ids := make([]uint64, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.Id)
+	}
+	result = tx.Model(&model.ScheduledJob{}).Where("id in ?", ids).Update("status", model.ScheduledJobStatusRunning)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+// CancelUserPendingJobs cancels every job of the user the worker has not claimed yet.
+func (s *scheduledJobRepositoryImpl) CancelUserPendingJobs(ctx context.Context, userID uint64) (int64, error) {
+	tx := GetTxContext(ctx, s.DB)
+	result := tx.Model(&model.ScheduledJob{}).Where("user_id=? and status=?", userID, model.ScheduledJobStatusPending).
+		Update("status", model.ScheduledJobStatusCancelled)
+	return result.RowsAffected, result.Error
+}
+
+func (s *scheduledJobRepositoryImpl) FinishScheduledJob(ctx context.Context, jobID uint64) error {
+	tx := GetTxContext(ctx, s.DB)
+	return tx.Model(&model.ScheduledJob{}).Where("id=?", jobID).Updates(map[string]interface{}{
+		"status":      model.ScheduledJobStatusDone,
+		"finish_time": time.Now(),
+	}).Error
+}
+
+func (s *scheduledJobRepositoryImpl) FailScheduledJob(ctx context.Context, jobID uint64, reason string) error {
+	tx := GetTxContext(ctx, s.DB)
+	return tx.Model(&model.ScheduledJob{}).Where("id=?", jobID).Updates(map[string]interface{}{
+		"status":      model.ScheduledJobStatusFailed,
+		"error":       reason,
+		"finish_time": time.Now(),
+	}).Error
+}
+
+// FetchFiredReminders lists the reminders of the user that fired and were not acknowledged yet,
+// the oldest first.
+func (s *scheduledJobRepositoryImpl) FetchFiredReminders(ctx context.Context, userID uint64, limit int) ([]*model.ScheduledJob, error) {
+	tx := GetTxContext(ctx, s.DB)
+	jobs := []*model.ScheduledJob{}
+	result := tx.Where("user_id=? and kind=? and status=? and ack_time is null",
+		userID, model.ScheduledJobKindReminder, model.ScheduledJobStatusDone).
+		Order("finish_time, id").Limit(limit).Find(&jobs)
+	return jobs, result.Error
+}
+
+// AckReminders acknowledges fired reminders of the user, other ids are ignored.
+func (s *scheduledJobRepositoryImpl) AckReminders(ctx context.Context, userID uint64, jobIDs []uint64) (int64, error) {
+	tx := GetTxContext(ctx, s.DB)
+	result := tx.Model(&model.ScheduledJob{}).
+		Where("id in ? and user_id=? and kind=? and status=? and ack_time is null",
+			jobIDs, userID, model.ScheduledJobKindReminder, model.ScheduledJobStatusDone).
+		Update("ack_time", time.Now())
+	return result.RowsAffected, result.Error
+}
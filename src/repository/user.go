@@ -15,9 +15,16 @@ import (
 type AccountRepository interface {
 	UserRegister(ctx context.Context, username string, password string, name string, email string, birthday time.Time) (*model.User, bool, error)
 	SelectUserByName(ctx context.Context, username string) (*model.User, bool, error)
+	SelectUserByEmail(ctx context.Context, email string) (*model.User, bool, error)
 	UpdatePassword(ctx context.Context, ID uint64, newHashedPassword string) (ok bool, err error)
 	UserInfo(ctx context.Context, ID uint64) (*model.User, error)
+	UsersInfo(ctx context.Context, IDs []uint64) ([]*model.User, error)
 	CheckUserExist(ctx context.Context, ID uint64) (exist bool, err error)
+	AccountStatus(ctx context.Context, ID uint64) (*model.User, error)
+	SearchUsers(ctx context.Context, keyword string, suspendedOnly bool, skip int, pageSize int) ([]*model.User, error)
+	SuspendUser(ctx context.Context, ID uint64, reason string) (ok bool, err error)
+	UnsuspendUser(ctx context.Context, ID uint64) (ok bool, err error)
+	SetPlatformRole(ctx context.Context, ID uint64, role string) (ok bool, err error)
 }
 
 type accountRepositoryImpl struct {
@@ -66,10 +73,20 @@ func (a *accountRepositoryImpl) UserInfo(ctx context.Context, ID uint64) (*model
 	return &user, result.Error
 }
 
+func (a *accountRepositoryImpl) UsersInfo(ctx context.Context, IDs []uint64) ([]*model.User, error) {
+	tx := GetTxContext(ctx, a.DB)
+	users := []*model.User{}
+	if len(IDs) == 0 {
+		return users, nil
+	}
+	result := tx.Select("Id", "Username", "Name", "Email").Where("id in ?", IDs).Find(&users)
+	return users, result.Error
+}
+
 func (a *accountRepositoryImpl) SelectUserByName(ctx context.Context, username string) (*model.User, bool, error) {
 	tx := GetTxContext(ctx, a.DB)
 	var user = model.User{Username: username}
-	result := tx.Select("id", "username", "password").Where("username=?", username).First(&user)
+	result := tx.Select("id", "username", "password", "suspend_time").Where("username=?", username).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, false, nil
@@ -79,6 +96,18 @@ func (a *accountRepositoryImpl) SelectUserByName(ctx context.Context, username s
 	return &user, true, nil
 }
 
+func (a *accountRepositoryImpl) SelectUserByEmail(ctx context.Context, email string) (*model.User, bool, error) {
+	tx := GetTxContext(ctx, a.DB)
+	var user model.User
+	result := tx.Select("id", "username", "name", "email").Where("lower(email) = lower(?)", email).Order("id").First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	} else if result.Error != nil {
+		return nil, false, result.Error
+	}
+	return &user, true, nil
+}
+
 func (a *accountRepositoryImpl) UpdatePassword(ctx context.Context, ID uint64, newHashedPassword string) (bool, error) {
 	tx := GetTxContext(ctx, a.DB)
 	ctx, span := a.tracer.Start(ctx, "UpdatePassword")
@@ -105,3 +134,69 @@ func (a *accountRepositoryImpl) CheckUserExist(ctx context.Context, ID uint64) (
 	}
 	return true, nil
 }
+
+// AccountStatus loads the platform role and suspension of a user, nil when the user does not exist.
+func (a *accountRepositoryImpl) AccountStatus(ctx context.Context, ID uint64) (*model.User, error) {
+	tx := GetTxContext(ctx, a.DB)
+	var user model.User
+	result := tx.Select("id", "platform_role", "suspend_time", "suspend_reason").Where("id=?", ID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// SearchUsers matches keyword against the id, username, name and email of users, an empty keyword
+// lists everybody.
+func (a *accountRepositoryImpl) SearchUsers(ctx context.Context, keyword string, suspendedOnly bool, skip int, pageSize int) ([]*model.User, error) {
+	tx := GetTxContext(ctx, a.DB)
+	users := []*model.User{}
+	tx = tx.Select("id", "username", "name", "email", "platform_role", "suspend_time", "suspend_reason", "create_time")
+	if keyword != "" {
+		pattern := "%" + escapeLike(keyword) + "%"
+		tx = tx.Where("id::text = ? or username ilike ? or name ilike ? or email ilike ?", keyword, pattern, pattern, pattern)
+	}
+	if suspendedOnly {
+		tx = tx.Where("suspend_time is not null")
+	}
+	result := tx.Order("id").Offset(skip).Limit(pageSize).Find(&users)
+	return users, result.Error
+}
+
+// SuspendUser is false when the user does not exist or is suspended already.
+func (a *accountRepositoryImpl) SuspendUser(ctx context.Context, ID uint64, reason string) (bool, error) {
+	tx := GetTxContext(ctx, a.DB)
+	result := tx.Model(&model.User{}).Where("id=? and suspend_time is null", ID).Updates(map[string]interface{}{
+		"suspend_time":   time.Now(),
+		"suspend_reason": reason,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnsuspendUser is false when the user does not exist or is not suspended.
+func (a *accountRepositoryImpl) UnsuspendUser(ctx context.Context, ID uint64) (bool, error) {
+	tx := GetTxContext(ctx, a.DB)
+	result := tx.Model(&model.User{}).Where("id=? and suspend_time is not null", ID).Updates(map[string]interface{}{
+		"suspend_time":   nil,
+		"suspend_reason": "",
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (a *accountRepositoryImpl) SetPlatformRole(ctx context.Context, ID uint64, role string) (bool, error) {
+	tx := GetTxContext(ctx, a.DB)
+	result := tx.Model(&model.User{}).Where("id=?", ID).Update("platform_role", role)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
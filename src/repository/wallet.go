@@ -7,6 +7,7 @@ import (
 	"errors"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
@@ -28,7 +29,10 @@ type walletRepositoryImpl struct {
 var wallet WalletRepository
 
 func init() {
-	wallet = &walletRepositoryImpl{DB: src.GlobalConfig.DB}
+	wallet = &walletRepositoryImpl{
+		DB:     src.GlobalConfig.DB,
+		tracer: otel.Tracer("walletRepository"),
+	}
 }
 
 func GetWalletRepository() WalletRepository {
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"ChatRoomAPI/src/cache"
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/repository"
+	"context"
+)
+
+type BlockService interface {
+	BlockUser(ctx context.Context, req *dto.BlockUserRequest) (*dto.BlockUserResponse, *dtoError.ServiceError)
+	UnblockUser(ctx context.Context, req *dto.UnblockUserRequest) (*dto.UnblockUserResponse, *dtoError.ServiceError)
+	FetchBlockedUsers(ctx context.Context, req *dto.FetchBlockedUsersRequest) (*dto.FetchBlockedUsersResponse, *dtoError.ServiceError)
+}
+
+type blockServiceImpl struct {
+	blockRepo  repository.BlockRepository
+	userRepo   repository.AccountRepository
+	blockCache cache.BlockCache
+	errWarpper dtoError.ServiceErrorWarpper
+	logger     logger.Logger
+}
+
+var block BlockService
+
+func init() {
+	block = &blockServiceImpl{
+		blockRepo:  repository.GetBlockRepository(),
+		userRepo:   repository.GetAccountRepository(),
+		blockCache: cache.GetBlockCache(),
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+		logger:     logger.NewLogger(),
+	}
+}
+
+func GetBlockService() BlockService {
+	return block
+}
+
+func (b *blockServiceImpl) BlockUser(ctx context.Context, req *dto.BlockUserRequest) (*dto.BlockUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	if req.UserID == req.BlockedUserID {
+		return nil, b.errWarpper.NewCannotBlockSelfError(req.UserID)
+	}
+
+	userExist, err := b.userRepo.CheckUserExist(ctx, req.BlockedUserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.userRepo.CheckUserExist", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !userExist {
+		return nil, b.errWarpper.NewUserNotExist(req.BlockedUserID)
+	}
+
+	created, err := b.blockRepo.BlockUser(ctx, req.UserID, req.BlockedUserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockRepo.BlockUser", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !created {
+		return nil, b.errWarpper.NewUserAlreadyBlockedError(req.UserID, req.BlockedUserID)
+	}
+
+	err = b.blockCache.ClearBlockCacheByUser(ctx, req.UserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockCache.ClearBlockCacheByUser", req, err)
+	}
+	return &dto.BlockUserResponse{}, nil
+}
+
+func (b *blockServiceImpl) UnblockUser(ctx context.Context, req *dto.UnblockUserRequest) (*dto.UnblockUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	ok, err := b.blockRepo.UnblockUser(ctx, req.UserID, req.BlockedUserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockRepo.UnblockUser", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, b.errWarpper.NewUserNotBlockedError(req.UserID, req.BlockedUserID)
+	}
+
+	err = b.blockCache.ClearBlockCacheByUser(ctx, req.UserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockCache.ClearBlockCacheByUser", req, err)
+	}
+	return &dto.UnblockUserResponse{}, nil
+}
+
+func (b *blockServiceImpl) FetchBlockedUsers(ctx context.Context, req *dto.FetchBlockedUsersRequest) (*dto.FetchBlockedUsersResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	users, err := b.blockRepo.FetchBlockedUsers(ctx, req.UserID, skip, pageSize)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockRepo.FetchBlockedUsers", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchBlockedUsersResponse{UserInfos: make([]dto.UserInfo, len(users))}
+	for i, user := range users {
+		answer.UserInfos[i].UserID = user.Id
+		answer.UserInfos[i].Username = user.Username
+		answer.UserInfos[i].Email = user.Email
+	}
+	return &answer, nil
+}
+
+// ====================================================================================
+
+// blockFilter answers "has user A blocked user B" for other services. The block list
+// is read from redis first so filtering a page of messages costs no extra db query.
+type blockFilter struct {
+	blockRepo  repository.BlockRepository
+	blockCache cache.BlockCache
+	logger     logger.Logger
+}
+
+func newBlockFilter() *blockFilter {
+	return &blockFilter{
+		blockRepo:  repository.GetBlockRepository(),
+		blockCache: cache.GetBlockCache(),
+		logger:     logger.NewLogger(),
+	}
+}
+
+func (b *blockFilter) blockedUserSet(ctx context.Context, userID uint64) (map[uint64]struct{}, error) {
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"userId": userID}
+
+	blocked, keyExist, err := b.blockCache.GetBlockedUserIDs(ctx, userID)
+	if err == nil && keyExist {
+		return blocked, nil
+	} else if err != nil {
+		b.logger.Error(requestId, "b.blockCache.GetBlockedUserIDs", data, err)
+	}
+
+	ids, err := b.blockRepo.GetBlockedUserIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.blockCache.StoreBlockedUserIDs(ctx, userID, ids)
+	if err != nil {
+		b.logger.Error(requestId, "b.blockCache.StoreBlockedUserIDs", data, err)
+	}
+
+	blocked = make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		blocked[id] = struct{}{}
+	}
+	return blocked, nil
+}
+
+// isBlocked reports whether userID has blocked targetUserID.
+func (b *blockFilter) isBlocked(ctx context.Context, userID uint64, targetUserID uint64) (bool, error) {
+	blocked, err := b.blockedUserSet(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	_, ok := blocked[targetUserID]
+	return ok, nil
+}
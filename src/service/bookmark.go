@@ -0,0 +1,188 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+)
+
+const defaultBookmarkPageSize = 20
+
+type BookmarkService interface {
+	SaveBookmark(ctx context.Context, req *dto.SaveBookmarkRequest) (*dto.SaveBookmarkResponse, *dtoError.ServiceError)
+	UpdateBookmark(ctx context.Context, req *dto.UpdateBookmarkRequest) (*dto.UpdateBookmarkResponse, *dtoError.ServiceError)
+	DeleteBookmark(ctx context.Context, req *dto.DeleteBookmarkRequest) (*dto.DeleteBookmarkResponse, *dtoError.ServiceError)
+	FetchBookmarks(ctx context.Context, req *dto.FetchBookmarksRequest) (*dto.FetchBookmarksResponse, *dtoError.ServiceError)
+	FetchBookmarkFolders(ctx context.Context, req *dto.FetchBookmarkFoldersRequest) (*dto.FetchBookmarkFoldersResponse, *dtoError.ServiceError)
+}
+
+type bookmarkServiceImpl struct {
+	bookmarkRepo repository.BookmarkRepository
+	roomRepo     repository.RoomRepository
+	messageRepo  repository.MessageRepository
+	errWarpper   dtoError.ServiceErrorWarpper
+	logger       logger.Logger
+}
+
+var bookmarkService BookmarkService
+
+func init() {
+	bookmarkService = &bookmarkServiceImpl{
+		bookmarkRepo: repository.GetBookmarkRepository(),
+		roomRepo:     repository.GetRoomRepository(),
+		messageRepo:  repository.GetMessageRepository(),
+		errWarpper:   dtoError.GetServiceErrorWarpper(),
+		logger:       logger.NewLogger(),
+	}
+}
+
+func GetBookmarkService() BookmarkService {
+	return bookmarkService
+}
+
+// SaveBookmark needs the user to be in the room of the message, saving a message twice updates
+// the note and folder of the existing bookmark.
+func (b *bookmarkServiceImpl) SaveBookmark(ctx context.Context, req *dto.SaveBookmarkRequest) (*dto.SaveBookmarkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	inRoom, err := b.roomRepo.CheckUserInRoom(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.roomRepo.CheckUserInRoom", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		return nil, b.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	message, err := b.messageRepo.GetMessage(ctx, req.RoomID, req.MessageID)
+	if err != nil {
+		b.logger.Error(requestId, "b.messageRepo.GetMessage", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if message == nil {
+		return nil, b.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	bookmark := model.Bookmark{
+		UserID:    req.UserID,
+		MessageID: req.MessageID,
+		RoomID:    req.RoomID,
+		Note:      req.Note,
+		Folder:    req.Folder,
+	}
+	err = b.bookmarkRepo.SaveBookmark(ctx, &bookmark)
+	if err != nil {
+		b.logger.Error(requestId, "b.bookmarkRepo.SaveBookmark", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.SaveBookmarkResponse{BookmarkID: bookmark.Id}, nil
+}
+
+func (b *bookmarkServiceImpl) UpdateBookmark(ctx context.Context, req *dto.UpdateBookmarkRequest) (*dto.UpdateBookmarkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	settings := map[string]interface{}{}
+	if req.Note != nil {
+		settings["note"] = *req.Note
+	}
+	if req.Folder != nil {
+		settings["folder"] = *req.Folder
+	}
+	if len(settings) == 0 {
+		return &dto.UpdateBookmarkResponse{}, nil
+	}
+
+	ok, err := b.bookmarkRepo.UpdateBookmark(ctx, req.UserID, req.BookmarkID, settings)
+	if err != nil {
+		b.logger.Error(requestId, "b.bookmarkRepo.UpdateBookmark", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, b.errWarpper.NewBookmarkNotExistError(req.BookmarkID)
+	}
+	return &dto.UpdateBookmarkResponse{}, nil
+}
+
+func (b *bookmarkServiceImpl) DeleteBookmark(ctx context.Context, req *dto.DeleteBookmarkRequest) (*dto.DeleteBookmarkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	ok, err := b.bookmarkRepo.DeleteBookmark(ctx, req.UserID, req.BookmarkID)
+	if err != nil {
+		b.logger.Error(requestId, "b.bookmarkRepo.DeleteBookmark", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, b.errWarpper.NewBookmarkNotExistError(req.BookmarkID)
+	}
+	return &dto.DeleteBookmarkResponse{}, nil
+}
+
+// FetchBookmarks lists the bookmarks of the rooms the user is still in, deleted messages are kept
+// in the list and marked so the user can clean them up.
+func (b *bookmarkServiceImpl) FetchBookmarks(ctx context.Context, req *dto.FetchBookmarksRequest) (*dto.FetchBookmarksResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	size := req.Size
+	if size == 0 {
+		size = defaultBookmarkPageSize
+	}
+	records, err := b.bookmarkRepo.FetchBookmarks(ctx, req.UserID, req.Folder, req.Cursor, size)
+	if err != nil {
+		b.logger.Error(requestId, "b.bookmarkRepo.FetchBookmarks", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchBookmarksResponse{Bookmarks: make([]dto.Bookmark, 0, len(records))}
+	for _, record := range records {
+		item := dto.Bookmark{
+			BookmarkID: record.Id,
+			RoomID:     record.RoomID,
+			RoomName:   record.RoomName,
+			Note:       record.Note,
+			Folder:     record.Folder,
+			CreateTime: common.TimeToUint64(record.CreatedAt),
+		}
+		if record.MessageCreatedAt == nil {
+			item.MessageDeleted = true
+		} else {
+			item.Message = &dto.Message{
+				ID:        record.MessageID,
+				UserID:    *record.MessageUserID,
+				Kind:      *record.MessageKind,
+				Content:   *record.MessageContent,
+				CreatedAt: common.TimeToUint64(*record.MessageCreatedAt),
+			}
+		}
+		answer.Bookmarks = append(answer.Bookmarks, item)
+	}
+	if len(records) == size {
+		answer.NextCursor = records[len(records)-1].Id
+	}
+	return &answer, nil
+}
+
+func (b *bookmarkServiceImpl) FetchBookmarkFolders(ctx context.Context, req *dto.FetchBookmarkFoldersRequest) (*dto.FetchBookmarkFoldersResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	b.logger.Info(requestId, "start", req, nil)
+	defer func() { b.logger.Info(requestId, "end", req, nil) }()
+
+	records, err := b.bookmarkRepo.FetchFolders(ctx, req.UserID)
+	if err != nil {
+		b.logger.Error(requestId, "b.bookmarkRepo.FetchFolders", req, err)
+		return nil, b.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchBookmarkFoldersResponse{Folders: make([]dto.BookmarkFolder, 0, len(records))}
+	for _, record := range records {
+		answer.Folders = append(answer.Folders, dto.BookmarkFolder{Folder: record.Folder, Count: record.Count})
+	}
+	return &answer, nil
+}
@@ -1,5 +1,11 @@
 package service
 
+import (
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+)
+
 func GetSkip(page int, pageSize int) (int, int) {
 	skip := 0
 	if page < 1 || pageSize < 1 {
@@ -8,3 +14,33 @@ func GetSkip(page int, pageSize int) (int, int) {
 	skip = (page - 1) * pageSize
 	return skip, pageSize
 }
+
+func roomMemberIDs(members []*model.RoomMember) []uint64 {
+	ids := make([]uint64, len(members))
+	for i, member := range members {
+		ids[i] = member.UserID
+	}
+	return ids
+}
+
+// roomIsArchived guards every path that adds to or changes a room or its members. An archived room
+// is read only: members can still leave and moderators can still remove members and messages, but
+// nothing is added or changed until it is unarchived.
+func roomIsArchived(ctx context.Context, roomRepo repository.RoomRepository, roomID uint64) (bool, error) {
+	return roomRepo.RoomArchived(ctx, roomID)
+}
+
+// roomIsFull locks the room for the rest of the transaction in ctx and reports whether its capacity
+// is reached. A zero capacity means unlimited. The lock queues concurrent joins behind each other,
+// but the transaction snapshot predates the lock, so the members are counted from committed data:
+// a join that held the lock before is always seen and the capacity can not be overshot.
+func roomIsFull(ctx context.Context, roomRepo repository.RoomRepository, roomID uint64) (bool, error) {
+	if _, err := roomRepo.LockRoom(ctx, roomID); err != nil {
+		return false, err
+	}
+	capacity, members, err := roomRepo.CommittedOccupancy(ctx, roomID)
+	if err != nil {
+		return false, err
+	}
+	return capacity > 0 && members >= int64(capacity), nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"ChatRoomAPI/src/cache"
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"ChatRoomAPI/src/unfurl"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// filterVerdict is what a content filter decided, action is empty when the message passes as is.
+type filterVerdict struct {
+	filter  string
+	action  string
+	content string
+	reason  string
+}
+
+// contentFilter is one step of the chain every new message goes through. A filter sees the
+// content as the filters before it left it, and can mask it or reject the message.
+type contentFilter interface {
+	name() string
+	check(ctx context.Context, room *model.Room, userID uint64, content string) (filterVerdict, error)
+}
+
+type contentFilterChain struct {
+	// filters judge the text, floodFilter judges the act of posting it and counts every call, so it
+	// is run apart by checkFlood.
+	filters     []contentFilter
+	floodFilter contentFilter
+	logRepo     repository.FilterLogRepository
+	logger      logger.Logger
+}
+
+func newContentFilterChain() *contentFilterChain {
+	return &contentFilterChain{
+		filters: []contentFilter{
+			&wordFilter{patterns: map[uint64]*roomWordPattern{}},
+			&linkLimitFilter{},
+		},
+		floodFilter: &floodFilter{flood: cache.GetFloodCache()},
+		logRepo:     repository.GetFilterLogRepository(),
+		logger:      logger.NewLogger(),
+	}
+}
+
+// run returns the content to store, or the verdict of the filter that rejected the message.
+// Decisions are logged for moderators with ctx rather than the message transaction, so a
+// rejection is kept although the message is rolled back.
+func (c *contentFilterChain) run(ctx context.Context, room *model.Room, userID uint64, content string) (string, *filterVerdict, error) {
+	return c.runFilters(ctx, c.filters, room, userID, content)
+}
+
+// checkFlood counts the content toward the flood limit and returns the verdict when it is over.
+// Callers run it last, right before the message is stored, so only messages that passed every
+// other check and limit are counted.
+func (c *contentFilterChain) checkFlood(ctx context.Context, room *model.Room, userID uint64, content string) (*filterVerdict, error) {
+	_, rejected, err := c.runFilters(ctx, []contentFilter{c.floodFilter}, room, userID, content)
+	return rejected, err
+}
+
+// runPoll filters the question and every option of a poll, the poll is rejected when any of them
+// is. The question is checked for flood by the caller like the content of a message.
+func (c *contentFilterChain) runPoll(ctx context.Context, room *model.Room, userID uint64, question string, options []string) (string, []string, *filterVerdict, error) {
+	filteredOptions := make([]string, len(options))
+	for i, option := range options {
+		filtered, rejected, err := c.run(ctx, room, userID, option)
+		if err != nil || rejected != nil {
+			return "", nil, rejected, err
+		}
+		filteredOptions[i] = filtered
+	}
+
+	filteredQuestion, rejected, err := c.run(ctx, room, userID, question)
+	if err != nil || rejected != nil {
+		return "", nil, rejected, err
+	}
+	return filteredQuestion, filteredOptions, nil, nil
+}
+
+func (c *contentFilterChain) runFilters(ctx context.Context, filters []contentFilter, room *model.Room, userID uint64, content string) (string, *filterVerdict, error) {
+	original := content
+	for _, filter := range filters {
+		verdict, err := filter.check(ctx, room, userID, content)
+		if err != nil {
+			return "", nil, err
+		} else if verdict.action == "" {
+			continue
+		}
+		verdict.filter = filter.name()
+		c.log(ctx, room.Id, userID, original, verdict)
+		if verdict.action == model.FilterActionReject {
+			return "", &verdict, nil
+		}
+		content = verdict.content
+	}
+	return content, nil, nil
+}
+
+func (c *contentFilterChain) log(ctx context.Context, roomID uint64, userID uint64, content string, verdict filterVerdict) {
+	entry := model.FilterLog{
+		RoomID:  roomID,
+		UserID:  userID,
+		Filter:  verdict.filter,
+		Action:  verdict.action,
+		Reason:  verdict.reason,
+		Content: content,
+	}
+	if err := c.logRepo.AddFilterLog(ctx, &entry); err != nil {
+		c.logger.Error(common.GetUUID(ctx), "c.logRepo.AddFilterLog", entry, err)
+	}
+}
+
+// wordFilter masks or rejects whole words of the server list and the room list, depending on the
+// filter action of the room. The pattern of a room is built once and rebuilt when its word list
+// changes, the server list only changes with a restart.
+type wordFilter struct {
+	mu       sync.Mutex
+	patterns map[uint64]*roomWordPattern
+}
+
+type roomWordPattern struct {
+	words   []string
+	pattern *regexp.Regexp
+}
+
+func (w *wordFilter) name() string {
+	return "word"
+}
+
+func (w *wordFilter) check(ctx context.Context, room *model.Room, userID uint64, content string) (filterVerdict, error) {
+	if room.FilterAction == model.FilterActionOff {
+		return filterVerdict{}, nil
+	}
+	pattern := w.pattern(room)
+	if pattern == nil || !pattern.MatchString(content) {
+		return filterVerdict{}, nil
+	}
+
+	if room.FilterAction == model.FilterActionReject {
+		return filterVerdict{action: model.FilterActionReject, reason: "message contains a filtered word"}, nil
+	}
+	masked := pattern.ReplaceAllStringFunc(content, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+	return filterVerdict{action: model.FilterActionMask, content: masked, reason: "filtered words were masked"}, nil
+}
+
+func (w *wordFilter) pattern(room *model.Room) *regexp.Regexp {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cached, ok := w.patterns[room.Id]; ok && slices.Equal(cached.words, room.FilterWords) {
+		return cached.pattern
+	}
+	words := slices.Clone(room.FilterWords)
+	pattern := wordPattern(append(slices.Clone(repository.FilterWords()), words...))
+	w.patterns[room.Id] = &roomWordPattern{words: words, pattern: pattern}
+	return pattern
+}
+
+// wordPattern matches any of words case insensitively, nil when there is none. Ends made of latin
+// letters or digits must be word boundaries, so "class" does not match "ass". Other scripts have
+// no spaces between words and match anywhere.
+func wordPattern(words []string) *regexp.Regexp {
+	alternatives := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		alternative := regexp.QuoteMeta(word)
+		if first, _ := utf8.DecodeRuneInString(word); isASCIIWordRune(first) {
+			alternative = `\b` + alternative
+		}
+		if last, _ := utf8.DecodeLastRuneInString(word); isASCIIWordRune(last) {
+			alternative += `\b`
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(alternatives, "|") + `)`)
+}
+
+func isASCIIWordRune(r rune) bool {
+	return r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// linkLimitFilter rejects messages with more distinct links than the room allows.
+type linkLimitFilter struct{}
+
+func (l *linkLimitFilter) name() string {
+	return "link_limit"
+}
+
+func (l *linkLimitFilter) check(ctx context.Context, room *model.Room, userID uint64, content string) (filterVerdict, error) {
+	limit := int(room.MaxLinks)
+	if limit == 0 {
+		limit = repository.MessageMaxLinks()
+	}
+	if len(unfurl.ExtractURLs(content, limit+1)) > limit {
+		return filterVerdict{action: model.FilterActionReject, reason: fmt.Sprintf("message has more than %d links", limit)}, nil
+	}
+	return filterVerdict{}, nil
+}
+
+// floodFilter rejects a user sending the same text to a room over and over. Case and spacing
+// are ignored when comparing.
+type floodFilter struct {
+	flood cache.FloodCache
+}
+
+func (f *floodFilter) name() string {
+	return "flood"
+}
+
+func (f *floodFilter) check(ctx context.Context, room *model.Room, userID uint64, content string) (filterVerdict, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	flooding, err := f.flood.Repeat(ctx, room.Id, userID, normalized)
+	if err != nil {
+		return filterVerdict{}, err
+	} else if flooding {
+		return filterVerdict{action: model.FilterActionReject, reason: "the same message was sent too often"}, nil
+	}
+	return filterVerdict{}, nil
+}
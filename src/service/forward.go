@@ -0,0 +1,171 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// ForwardMessage posts the content of a message of another room the user is in. The copy keeps
+// the original author, room and time, and the stickers in it are checked against what the
+// forwarder owns, the same as a message typed by them.
+func (m *messageServiceImpl) ForwardMessage(ctx context.Context, req *dto.ForwardMessageRequest) (*dto.ForwardMessageResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	defer repository.RollbackOnPanic(tx)
+	roomInfo, serviceErr := m.checkPost(ctx, txContext, req.RoomID, req.UserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	reference, content, serviceErr := m.loadReference(ctx, txContext, model.MessageReferenceForward, req.UserID, req.SourceRoomID, req.SourceMessageID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	newContent, err := m.stickers.strip(ctx, content, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.stickers.strip", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if strings.TrimSpace(newContent) == "" {
+		tx.Rollback()
+		return nil, m.errWarpper.NewInvalidReferenceError(req.SourceMessageID, req.SourceRoomID, "nothing is left once stickers the user does not own are removed")
+	}
+	newContent, rejected, err := m.filters.run(ctx, roomInfo, req.UserID, newContent)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.filters.run", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
+	}
+
+	payload, err := json.Marshal(reference)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "json.Marshal", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	serviceErr = m.acquirePost(ctx, roomInfo, req.UserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	rejected, err = m.filters.checkFlood(ctx, roomInfo, req.UserID, newContent)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.filters.checkFlood", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
+	}
+
+	message, err := m.messageRepo.AddMessage(txContext, req.RoomID, req.UserID, newContent, string(payload))
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.messageRepo.AddMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		m.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, m.errWarpper.NewDBCommitServiceError(err)
+	}
+	m.unfurler.unfurlAsync(requestId, message.ID, newContent)
+	return &dto.ForwardMessageResponse{
+		ID:        message.ID,
+		CreatedAt: common.TimeToUint64(message.CreatedAt),
+		Content:   newContent,
+	}, nil
+}
+
+// loadReference checks that the user is in the source room and returns the attribution for a
+// forward or quote of the message, together with its content. Forwarding a forwarded message
+// keeps pointing at the original one.
+func (m *messageServiceImpl) loadReference(ctx context.Context, txContext context.Context, referenceType string,
+	userID uint64, roomID uint64, messageID uint64) (*model.MessageReference, string, *dtoError.ServiceError) {
+
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"room_id": roomID, "message_id": messageID, "user_id": userID}
+
+	inRoom, err := m.roomRepo.CheckUserInRoom(txContext, roomID, userID)
+	if err != nil {
+		m.logger.Error(requestId, "m.roomRepo.CheckUserInRoom", data, err)
+		return nil, "", m.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		return nil, "", m.errWarpper.NewUserNotInRoomError(userID, roomID)
+	}
+
+	source, err := m.messageRepo.GetMessage(txContext, roomID, messageID)
+	if err != nil {
+		m.logger.Error(requestId, "m.messageRepo.GetMessage", data, err)
+		return nil, "", m.errWarpper.NewDBServiceError(err)
+	} else if source == nil {
+		return nil, "", m.errWarpper.NewMessageNotExistError(messageID, roomID)
+	} else if source.Kind == model.MessageKindSystem {
+		return nil, "", m.errWarpper.NewInvalidReferenceError(messageID, roomID, "system messages can not be referenced")
+	} else if source.Kind == model.MessageKindPoll && referenceType == model.MessageReferenceForward {
+		return nil, "", m.errWarpper.NewInvalidReferenceError(messageID, roomID, "polls can not be forwarded")
+	}
+
+	reference := &model.MessageReference{
+		Type:      referenceType,
+		RoomID:    source.RoomID,
+		MessageID: source.ID,
+		UserID:    source.UserID,
+		CreatedAt: source.CreatedAt,
+	}
+	if earlier := parseMessageReference(source); earlier != nil && earlier.Type == model.MessageReferenceForward {
+		reference.RoomID = earlier.RoomID
+		reference.MessageID = earlier.MessageID
+		reference.UserID = earlier.UserID
+		reference.CreatedAt = earlier.CreatedAt
+	}
+	if referenceType == model.MessageReferenceQuote {
+		reference.Content = source.Content
+	}
+	return reference, source.Content, nil
+}
+
+// parseMessageReference decodes the payload of a forwarded or quoting user message, nil otherwise.
+func parseMessageReference(message *model.Message) *model.MessageReference {
+	if message.Kind != model.MessageKindUser || message.Payload == "" {
+		return nil
+	}
+	reference := model.MessageReference{}
+	if err := json.Unmarshal([]byte(message.Payload), &reference); err != nil {
+		return nil
+	}
+	return &reference
+}
+
+func referenceResponse(message *model.Message) *dto.Reference {
+	reference := parseMessageReference(message)
+	if reference == nil {
+		return nil
+	}
+	return &dto.Reference{
+		Type:       reference.Type,
+		RoomID:     reference.RoomID,
+		MessageID:  reference.MessageID,
+		UserID:     reference.UserID,
+		CreateTime: common.TimeToUint64(reference.CreatedAt),
+		Content:    reference.Content,
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+)
+
+type ImportService interface {
+	CreateImport(ctx context.Context, req *dto.CreateImportRequest) (*dto.CreateImportResponse, *dtoError.ServiceError)
+	FetchImport(ctx context.Context, req *dto.FetchImportRequest) (*dto.FetchImportResponse, *dtoError.ServiceError)
+}
+
+type importServiceImpl struct {
+	importRepo repository.ImportRepository
+	errWarpper dtoError.ServiceErrorWarpper
+	logger     logger.Logger
+}
+
+var importService ImportService
+
+func init() {
+	importService = &importServiceImpl{
+		importRepo: repository.GetImportRepository(),
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+		logger:     logger.NewLogger(),
+	}
+}
+
+func GetImportService() ImportService {
+	return importService
+}
+
+// CreateImport queues an uploaded archive, the worker imports it and the requester owns the rooms
+// it creates.
+func (i *importServiceImpl) CreateImport(ctx context.Context, req *dto.CreateImportRequest) (*dto.CreateImportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	logData := map[string]any{"user_id": req.UserID, "source": req.Source, "size": len(req.Archive)}
+	i.logger.Info(requestId, "start", logData, nil)
+	defer func() { i.logger.Info(requestId, "end", logData, nil) }()
+
+	job := model.ImportJob{
+		Source:            req.Source,
+		RequestedByUserID: req.UserID,
+		Status:            model.ImportStatusPending,
+		Archive:           req.Archive,
+	}
+	err := i.importRepo.CreateImportJob(ctx, &job)
+	if err != nil {
+		i.logger.Error(requestId, "i.importRepo.CreateImportJob", logData, err)
+		return nil, i.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.CreateImportResponse{ImportID: job.Id}, nil
+}
+
+func (i *importServiceImpl) FetchImport(ctx context.Context, req *dto.FetchImportRequest) (*dto.FetchImportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	i.logger.Info(requestId, "start", req, nil)
+	defer func() { i.logger.Info(requestId, "end", req, nil) }()
+
+	job, err := i.importRepo.GetImportJob(ctx, req.ImportID)
+	if err != nil {
+		i.logger.Error(requestId, "i.importRepo.GetImportJob", req, err)
+		return nil, i.errWarpper.NewDBServiceError(err)
+	} else if job == nil || job.RequestedByUserID != req.UserID {
+		return nil, i.errWarpper.NewImportNotExistError(req.ImportID)
+	}
+
+	answer := dto.FetchImportResponse{
+		ImportID:     job.Id,
+		Source:       job.Source,
+		Status:       job.Status,
+		Error:        job.Error,
+		RoomCount:    job.RoomCount,
+		MessageCount: job.MessageCount,
+		CreateTime:   common.TimeToUint64(job.CreatedAt),
+	}
+	if job.FinishedAt != nil {
+		answer.FinishTime = common.TimeToUint64(*job.FinishedAt)
+	}
+	return &answer, nil
+}
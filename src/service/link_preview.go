@@ -0,0 +1,132 @@
+package service
+
+import (
+	"ChatRoomAPI/src/cache"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"ChatRoomAPI/src/unfurl"
+	"context"
+	"time"
+)
+
+const (
+	// maxPreviewPerMessage bounds the links of a message that get a preview.
+	maxPreviewPerMessage = 3
+	// maxConcurrentUnfurl bounds the messages unfurled at once, messages beyond it get no preview.
+	maxConcurrentUnfurl  = 16
+	unfurlMessageTimeout = 20 * time.Second
+)
+
+// linkUnfurler adds previews to the links of a message in the background. It runs after the
+// message committed, so a slow or failing page never holds up or fails the post.
+type linkUnfurler struct {
+	fetcher      *unfurl.Fetcher
+	previewRepo  repository.LinkPreviewRepository
+	previewCache cache.LinkPreviewCache
+	logger       logger.Logger
+	slots        chan struct{}
+}
+
+func newLinkUnfurler() *linkUnfurler {
+	return &linkUnfurler{
+		fetcher:      unfurl.NewFetcher(unfurl.DefaultTimeout, unfurl.DefaultMaxBodyBytes),
+		previewRepo:  repository.GetLinkPreviewRepository(),
+		previewCache: cache.GetLinkPreviewCache(),
+		logger:       logger.NewLogger(),
+		slots:        make(chan struct{}, maxConcurrentUnfurl),
+	}
+}
+
+// unfurlAsync starts unfurling the links of content for messageID. It has to be called after the
+// commit of the message.
+func (u *linkUnfurler) unfurlAsync(requestId string, messageID uint64, content string) {
+	urls := unfurl.ExtractURLs(content, maxPreviewPerMessage)
+	if len(urls) == 0 {
+		return
+	}
+	select {
+	case u.slots <- struct{}{}:
+	default:
+		u.logger.Info(requestId, "unfurl skipped, too many in flight", messageID, nil)
+		return
+	}
+	go func() {
+		defer func() { <-u.slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), unfurlMessageTimeout)
+		defer cancel()
+		u.unfurl(ctx, requestId, messageID, urls)
+	}()
+}
+
+func (u *linkUnfurler) unfurl(ctx context.Context, requestId string, messageID uint64, urls []string) {
+	previews := make([]*model.LinkPreview, 0, len(urls))
+	for _, url := range urls {
+		preview := u.preview(ctx, requestId, url)
+		if preview.Empty() {
+			continue
+		}
+		previews = append(previews, &model.LinkPreview{
+			MessageID:   messageID,
+			URL:         url,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageURL:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		})
+	}
+	if err := u.previewRepo.SavePreviews(ctx, previews); err != nil {
+		u.logger.Error(requestId, "u.previewRepo.SavePreviews", messageID, err)
+	}
+}
+
+// preview answers from the cache when it can, a page that can not be loaded is cached as empty.
+func (u *linkUnfurler) preview(ctx context.Context, requestId string, url string) *cache.LinkPreviewCacheInfo {
+	cached, ok, err := u.previewCache.GetPreview(ctx, url)
+	if err != nil {
+		u.logger.Error(requestId, "u.previewCache.GetPreview", url, err)
+	} else if ok {
+		return cached
+	}
+
+	info := &cache.LinkPreviewCacheInfo{}
+	fetched, err := u.fetcher.Fetch(ctx, url)
+	if err != nil {
+		u.logger.Info(requestId, "unfurl failed", map[string]any{"url": url, "error": err.Error()}, nil)
+	} else {
+		info.Title = fetched.Title
+		info.Description = fetched.Description
+		info.ImageURL = fetched.ImageURL
+		info.SiteName = fetched.SiteName
+	}
+	if err := u.previewCache.StorePreview(ctx, url, info); err != nil {
+		u.logger.Error(requestId, "u.previewCache.StorePreview", url, err)
+	}
+	return info
+}
+
+// previewResponses groups the stored previews of messages by message id.
+func (u *linkUnfurler) previewResponses(ctx context.Context, messages []*model.Message) (map[uint64][]dto.LinkPreview, error) {
+	ids := make([]uint64, 0, len(messages))
+	for _, message := range messages {
+		if message.Kind == model.MessageKindUser {
+			ids = append(ids, message.ID)
+		}
+	}
+	previews, err := u.previewRepo.FetchPreviews(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	answer := map[uint64][]dto.LinkPreview{}
+	for _, preview := range previews {
+		answer[preview.MessageID] = append(answer[preview.MessageID], dto.LinkPreview{
+			URL:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageURL:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		})
+	}
+	return answer, nil
+}
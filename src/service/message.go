@@ -6,24 +6,43 @@ import (
 	"ChatRoomAPI/src/dto"
 	"ChatRoomAPI/src/dtoError"
 	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
 	"ChatRoomAPI/src/repository"
 	"context"
-	"strconv"
-	"strings"
+	"encoding/json"
+	"time"
 )
 
 type MessageService interface {
 	AddMessage(ctx context.Context, req *dto.AddMessageRequest) (*dto.AddMessageResponse, *dtoError.ServiceError)
 	FetchMessages(ctx context.Context, req *dto.FetchMessageRequest) (*dto.FetchMessageResponse, *dtoError.ServiceError)
+	DeleteMessage(ctx context.Context, req *dto.DeleteMessageRequest) (*dto.DeleteMessageResponse, *dtoError.ServiceError)
+	PinMessage(ctx context.Context, req *dto.PinMessageRequest) (*dto.PinMessageResponse, *dtoError.ServiceError)
+	UnpinMessage(ctx context.Context, req *dto.UnpinMessageRequest) (*dto.UnpinMessageResponse, *dtoError.ServiceError)
+	FetchPinnedMessages(ctx context.Context, req *dto.FetchPinnedMessagesRequest) (*dto.FetchPinnedMessagesResponse, *dtoError.ServiceError)
+	CreatePoll(ctx context.Context, req *dto.CreatePollRequest) (*dto.CreatePollResponse, *dtoError.ServiceError)
+	VotePoll(ctx context.Context, req *dto.VotePollRequest) (*dto.VotePollResponse, *dtoError.ServiceError)
+	ForwardMessage(ctx context.Context, req *dto.ForwardMessageRequest) (*dto.ForwardMessageResponse, *dtoError.ServiceError)
 }
 
+// maxPinnedMessages bounds the pins of a room, the pin list is always returned in full.
+const maxPinnedMessages = 50
+
 type messageServiceImpl struct {
 	messageRepo  repository.MessageRepository
 	roomRepo     repository.RoomRepository
-	stickerRepo  repository.StickerRepository
+	pinRepo      repository.PinnedMessageRepository
+	pollRepo     repository.PollRepository
 	errWarpper   dtoError.ServiceErrorWarpper
 	logger       logger.Logger
-	stickerCache cache.StickerCache
+	stickers     *stickerFilter
+	messageLimit cache.MessageLimitCache
+	blockFilter  *blockFilter
+	permission   *permissionEvaluator
+	events       *systemEventWriter
+	unfurler     *linkUnfurler
+	filters      *contentFilterChain
+	moderation   *moderationActions
 }
 
 var message MessageService
@@ -34,8 +53,16 @@ func init() {
 		roomRepo:     repository.GetRoomRepository(),
 		errWarpper:   dtoError.GetServiceErrorWarpper(),
 		logger:       logger.NewLogger(),
-		stickerRepo:  repository.GetStickerRepository(),
-		stickerCache: cache.GetStickerCache(),
+		stickers:     newStickerFilter(),
+		messageLimit: cache.GetMessageLimitCache(),
+		blockFilter:  newBlockFilter(),
+		pinRepo:      repository.GetPinnedMessageRepository(),
+		pollRepo:     repository.GetPollRepository(),
+		permission:   newPermissionEvaluator(),
+		events:       newSystemEventWriter(),
+		unfurler:     newLinkUnfurler(),
+		filters:      newContentFilterChain(),
+		moderation:   newModerationActions(),
 	}
 }
 
@@ -43,102 +70,79 @@ func GetMessageService() MessageService {
 	return message
 }
 
-func (m *messageServiceImpl) checkStickerFromContent(ctx context.Context, content string, userId uint64) (string, error) {
+func (m *messageServiceImpl) AddMessage(ctx context.Context, req *dto.AddMessageRequest) (*dto.AddMessageResponse, *dtoError.ServiceError) {
 	requestId := common.GetUUID(ctx)
-	data := map[string]any{"userId": userId, "content": content}
-
-	chunks := strings.Split(content, " ")
-	userStickerSetCacheMap, keyExist, err := m.stickerCache.GetAllStickerSetInfoByUser(ctx, userId)
-	if err != nil || !keyExist {
-		m.logger.Error(requestId, "m.stickerCache.GetAllStickerSetInfoByUser", data, err)
-		m.stickerCache.ClearAllStickerCacheByUser(ctx, userId)
-		stickerSetList, err := m.stickerRepo.GetAllAvailableStickersInfo(ctx, userId)
-		if err != nil {
-			return "", err
-		}
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
 
-		userStickerSetCacheMap = make(map[uint64]*cache.StickerSetCacheInfo)
-		for _, stickerSet := range stickerSetList {
-			stickerSetCacheInfo := &cache.StickerSetCacheInfo{
-				Id:       stickerSet.Id,
-				Name:     stickerSet.Name,
-				Author:   stickerSet.Author,
-				Price:    stickerSet.Price,
-				Stickers: make(map[uint64]*cache.StickerCacheInfo),
-			}
+	txContext, tx := repository.SetTxContext(ctx)
+	defer repository.RollbackOnPanic(tx)
+	roomInfo, serviceErr := m.checkPost(ctx, txContext, req.RoomID, req.UserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
 
-			for _, sticker := range stickerSet.Stickers {
-				stickerSetCacheInfo.Stickers[sticker.Id] = &cache.StickerCacheInfo{
-					Id:   sticker.Id,
-					Name: sticker.Name,
-				}
-			}
-			userStickerSetCacheMap[stickerSet.Id] = stickerSetCacheInfo
-		}
-		err = m.stickerCache.StoreStickerSetInfoByUser(ctx, userId, userStickerSetCacheMap)
-		if err != nil {
-			m.logger.Error(requestId, "m.stickerCache.StoreStickerSetInfoByUser", data, err)
-		}
+	newContent, err := m.stickers.strip(ctx, req.Content, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.stickers.strip", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+	newContent, rejected, err := m.filters.run(ctx, roomInfo, req.UserID, newContent)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.filters.run", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
 	}
 
-	for i, chunk := range chunks {
-		subchunks := strings.Split(chunk, "::")
-		if len(subchunks) != 3 {
-			continue
+	payload := ""
+	if req.QuoteMessageID != 0 {
+		quoteRoomID := req.QuoteRoomID
+		if quoteRoomID == 0 {
+			quoteRoomID = req.RoomID
 		}
-		if subchunks[0] != "sticker" {
-			continue
+		reference, _, serviceErr := m.loadReference(ctx, txContext, model.MessageReferenceQuote, req.UserID, quoteRoomID, req.QuoteMessageID)
+		if serviceErr != nil {
+			tx.Rollback()
+			return nil, serviceErr
 		}
-
-		stickerSetId, err := strconv.ParseUint(subchunks[1], 10, 64)
+		// the quoted text is shown again under the user's name, so it gets the same sticker check
+		reference.Content, err = m.stickers.strip(ctx, reference.Content, req.UserID)
 		if err != nil {
-			continue
+			tx.Rollback()
+			m.logger.Error(requestId, "m.stickers.strip", req, err)
+			return nil, m.errWarpper.NewDBServiceError(err)
 		}
-
-		stickerId, err := strconv.ParseUint(subchunks[2], 10, 64)
+		data, err := json.Marshal(reference)
 		if err != nil {
-			continue
-		}
-
-		StickerSetCache, ok := userStickerSetCacheMap[stickerSetId]
-		if !ok {
-			chunks[i] = ""
-		}
-
-		_, ok = StickerSetCache.Stickers[stickerId]
-		if !ok {
-			chunks[i] = ""
+			tx.Rollback()
+			m.logger.Error(requestId, "json.Marshal", req, err)
+			return nil, m.errWarpper.NewDBServiceError(err)
 		}
+		payload = string(data)
 	}
 
-	newContent := strings.Join(chunks, " ")
-	return newContent, nil
-}
-
-func (m *messageServiceImpl) AddMessage(ctx context.Context, req *dto.AddMessageRequest) (*dto.AddMessageResponse, *dtoError.ServiceError) {
-	requestId := common.GetUUID(ctx)
-	m.logger.Info(requestId, "start", req, nil)
-	defer func() { m.logger.Info(requestId, "end", req, nil) }()
-
-	txContext, tx := repository.SetTxContext(ctx)
-	InRoom, err := m.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
-	if err != nil {
+	serviceErr = m.acquirePost(ctx, roomInfo, req.UserID)
+	if serviceErr != nil {
 		tx.Rollback()
-		m.logger.Error(requestId, "m.roomRepo.CheckUserInRoom", req, err)
-		return nil, m.errWarpper.NewDBServiceError(err)
-	} else if !InRoom {
-		tx.Rollback()
-		return nil, m.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+		return nil, serviceErr
 	}
 
-	newContent, err := m.checkStickerFromContent(ctx, req.Content, req.UserID)
+	rejected, err = m.filters.checkFlood(ctx, roomInfo, req.UserID, newContent)
 	if err != nil {
 		tx.Rollback()
-		m.logger.Error(requestId, "m.checkStickerFromContent", req, err)
+		m.logger.Error(requestId, "m.filters.checkFlood", req, err)
 		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
 	}
 
-	message, err := m.messageRepo.AddMessage(txContext, req.RoomID, req.UserID, newContent)
+	message, err := m.messageRepo.AddMessage(txContext, req.RoomID, req.UserID, newContent, payload)
 	if err != nil {
 		m.logger.Error(requestId, "m.messageRepo.AddMessage", req, err)
 		tx.Rollback()
@@ -150,6 +154,7 @@ func (m *messageServiceImpl) AddMessage(ctx context.Context, req *dto.AddMessage
 		m.logger.Error(requestId, "tx.Commit", req, err)
 		return nil, m.errWarpper.NewDBCommitServiceError(err)
 	}
+	m.unfurler.unfurlAsync(requestId, message.ID, newContent)
 	return &dto.AddMessageResponse{
 		ID:        message.ID,
 		CreatedAt: common.TimeToUint64(message.CreatedAt),
@@ -157,6 +162,71 @@ func (m *messageServiceImpl) AddMessage(ctx context.Context, req *dto.AddMessage
 	}, nil
 }
 
+// checkPost runs the checks every new message of a user goes through: membership, mute, archived
+// room, the posting permission of the room mode and blocks in direct rooms. The caller rolls back
+// its transaction when an error is returned.
+func (m *messageServiceImpl) checkPost(ctx context.Context, txContext context.Context, roomID uint64, userID uint64) (*model.Room, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"room_id": roomID, "user_id": userID}
+
+	member, err := m.roomRepo.GetMember(txContext, roomID, userID)
+	if err != nil {
+		m.logger.Error(requestId, "m.roomRepo.GetMember", data, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if member == nil {
+		return nil, m.errWarpper.NewUserNotInRoomError(userID, roomID)
+	} else if member.MutedUntil != nil && member.MutedUntil.After(time.Now()) {
+		return nil, m.errWarpper.NewUserIsMutedError(userID, roomID, *member.MutedUntil)
+	}
+
+	roomInfo, err := m.roomRepo.ReadRoomInfo(txContext, roomID)
+	if err != nil {
+		m.logger.Error(requestId, "m.roomRepo.ReadRoomInfo", data, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if roomInfo == nil {
+		return nil, m.errWarpper.NewRoomNotExistError(roomID)
+	} else if roomInfo.ArchivedAt != nil {
+		return nil, m.errWarpper.NewRoomIsArchivedError(roomID)
+	}
+
+	// the membership looked up above decides whether the user may post in this room's mode
+	if perm := postPermission(roomInfo.Mode); !m.permission.granted(member, perm) {
+		return nil, m.errWarpper.NewPermissionDeniedError(userID, roomID, perm.String())
+	}
+
+	if roomInfo.Type == model.RoomTypeDirect {
+		for _, id := range roomMemberIDs(roomInfo.Members) {
+			if id == userID {
+				continue
+			}
+			blocked, err := m.blockFilter.isBlocked(ctx, id, userID)
+			if err != nil {
+				m.logger.Error(requestId, "m.blockFilter.isBlocked", data, err)
+				return nil, m.errWarpper.NewDBServiceError(err)
+			} else if blocked {
+				return nil, m.errWarpper.NewUserIsBlockedError(id, userID)
+			}
+		}
+	}
+	return roomInfo, nil
+}
+
+// acquirePost takes a slot of the slow mode and burst limits, it comes last so a message rejected
+// by another check does not use one up.
+func (m *messageServiceImpl) acquirePost(ctx context.Context, roomInfo *model.Room, userID uint64) *dtoError.ServiceError {
+	slowMode := time.Duration(roomInfo.SlowModeSecond) * time.Second
+	limited, retryAfter, err := m.messageLimit.Acquire(ctx, roomInfo.Id, userID, slowMode)
+	if err != nil {
+		m.logger.Error(common.GetUUID(ctx), "m.messageLimit.Acquire", map[string]any{"room_id": roomInfo.Id, "user_id": userID}, err)
+		return m.errWarpper.NewDBServiceError(err)
+	} else if limited == cache.MessageLimitSlowMode {
+		return m.errWarpper.NewSlowModeActiveError(roomInfo.Id, retryAfter)
+	} else if limited == cache.MessageLimitBurst {
+		return m.errWarpper.NewMessageBurstExceededError(roomInfo.Id, retryAfter)
+	}
+	return nil
+}
+
 func (m *messageServiceImpl) FetchMessages(ctx context.Context, req *dto.FetchMessageRequest) (*dto.FetchMessageResponse, *dtoError.ServiceError) {
 	requestId := common.GetUUID(ctx)
 	m.logger.Info(requestId, "start", req, nil)
@@ -186,16 +256,220 @@ func (m *messageServiceImpl) FetchMessages(ctx context.Context, req *dto.FetchMe
 		return nil, m.errWarpper.NewDBCommitServiceError(err)
 	}
 
+	blocked, err := m.blockFilter.blockedUserSet(ctx, req.UserID)
+	if err != nil {
+		m.logger.Error(requestId, "m.blockFilter.blockedUserSet", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	polls, err := m.pollResults(ctx, req.UserID, messages)
+	if err != nil {
+		m.logger.Error(requestId, "m.pollResults", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	previews, err := m.unfurler.previewResponses(ctx, messages)
+	if err != nil {
+		m.logger.Error(requestId, "m.unfurler.previewResponses", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
 	answer := &dto.FetchMessageResponse{
 		NextTimeCursor: common.TimeToUint64(nextCursor),
 	}
-	messageResp := make([]dto.Message, len(messages))
-	for i, message := range messages {
-		messageResp[i].ID = message.ID
-		messageResp[i].UserID = message.UserID
-		messageResp[i].Content = message.Content
-		messageResp[i].CreatedAt = common.TimeToUint64(message.CreatedAt)
+	messageResp := make([]dto.Message, 0, len(messages))
+	for _, message := range messages {
+		// the cursor still moves past hidden messages, so paging is not affected
+		if _, ok := blocked[message.UserID]; ok && message.Kind != model.MessageKindSystem {
+			continue
+		}
+		messageResp = append(messageResp, dto.Message{
+			ID:        message.ID,
+			UserID:    message.UserID,
+			Kind:      message.Kind,
+			Content:   message.Content,
+			Event:     parseSystemEvent(message),
+			Poll:      polls[message.ID],
+			Reference: referenceResponse(message),
+			Previews:  previews[message.ID],
+			CreatedAt: common.TimeToUint64(message.CreatedAt),
+		})
 	}
 	answer.Messages = messageResp
 	return answer, nil
 }
+
+// DeleteMessage lets the author remove their own message and members with the delete message
+// permission remove any message. Pins of the message go with it.
+func (m *messageServiceImpl) DeleteMessage(ctx context.Context, req *dto.DeleteMessageRequest) (*dto.DeleteMessageResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	serviceErr := m.moderation.deleteMessage(txContext, req, false)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		m.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, m.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.DeleteMessageResponse{}, nil
+}
+
+// PinMessage pins a message of the room, pinning it again is a no-op.
+func (m *messageServiceImpl) PinMessage(ctx context.Context, req *dto.PinMessageRequest) (*dto.PinMessageResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	// the room lock keeps concurrent pins from going over the limit
+	roomExist, err := m.roomRepo.LockRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.roomRepo.LockRoom", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		tx.Rollback()
+		return nil, m.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	_, allowed, err := m.permission.check(txContext, req.RoomID, req.UserID, permissionPin)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.permission.check", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, m.errWarpper.NewPermissionDeniedError(req.UserID, req.RoomID, permissionPin.String())
+	}
+
+	roomInfo, err := m.roomRepo.ReadRoomInfo(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.roomRepo.ReadRoomInfo", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if roomInfo.ArchivedAt != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	message, err := m.messageRepo.GetMessage(txContext, req.RoomID, req.MessageID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.messageRepo.GetMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if message == nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	count, err := m.pinRepo.CountPins(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.pinRepo.CountPins", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if count >= maxPinnedMessages {
+		tx.Rollback()
+		return nil, m.errWarpper.NewPinLimitReachedError(req.RoomID, maxPinnedMessages)
+	}
+
+	pin := model.PinnedMessage{RoomID: req.RoomID, MessageID: req.MessageID, PinnedByUserID: req.UserID}
+	created, err := m.pinRepo.PinMessage(txContext, &pin)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.pinRepo.PinMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !created {
+		tx.Rollback()
+		return &dto.PinMessageResponse{}, nil
+	}
+
+	event := dto.SystemEvent{Type: model.SystemEventMessagePinned, ActorUserID: req.UserID, MessageID: req.MessageID}
+	err = m.events.write(txContext, req.RoomID, event)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.events.write", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		m.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, m.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.PinMessageResponse{}, nil
+}
+
+func (m *messageServiceImpl) UnpinMessage(ctx context.Context, req *dto.UnpinMessageRequest) (*dto.UnpinMessageResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	archived, err := roomIsArchived(ctx, m.roomRepo, req.RoomID)
+	if err != nil {
+		m.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		return nil, m.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := m.permission.check(ctx, req.RoomID, req.UserID, permissionPin)
+	if err != nil {
+		m.logger.Error(requestId, "m.permission.check", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, m.errWarpper.NewPermissionDeniedError(req.UserID, req.RoomID, permissionPin.String())
+	}
+
+	ok, err := m.pinRepo.UnpinMessage(ctx, req.RoomID, req.MessageID)
+	if err != nil {
+		m.logger.Error(requestId, "m.pinRepo.UnpinMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, m.errWarpper.NewMessageNotPinnedError(req.MessageID, req.RoomID)
+	}
+	return &dto.UnpinMessageResponse{}, nil
+}
+
+// FetchPinnedMessages lists the pins of a room, oldest pin first.
+func (m *messageServiceImpl) FetchPinnedMessages(ctx context.Context, req *dto.FetchPinnedMessagesRequest) (*dto.FetchPinnedMessagesResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	inRoom, err := m.roomRepo.CheckUserInRoom(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		m.logger.Error(requestId, "m.roomRepo.CheckUserInRoom", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		return nil, m.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	records, err := m.pinRepo.FetchPins(ctx, req.RoomID)
+	if err != nil {
+		m.logger.Error(requestId, "m.pinRepo.FetchPins", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchPinnedMessagesResponse{Pins: make([]dto.PinnedMessage, len(records))}
+	for i, record := range records {
+		answer.Pins[i] = dto.PinnedMessage{
+			Message: dto.Message{
+				ID:        record.MessageID,
+				UserID:    record.UserID,
+				Kind:      record.Kind,
+				Content:   record.Content,
+				CreatedAt: common.TimeToUint64(record.CreatedAt),
+			},
+			PinnedByUserID: record.PinnedByUserID,
+			PinTime:        common.TimeToUint64(record.PinnedAt),
+		}
+	}
+	return &answer, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+// moderationActions are the steps shared by the moderator endpoints and by resolving a report. They
+// neither open nor end a transaction, the caller passes its transaction context so the action
+// commits together with whatever else it records.
+type moderationActions struct {
+	roomRepo        repository.RoomRepository
+	messageRepo     repository.MessageRepository
+	pinRepo         repository.PinnedMessageRepository
+	roomBanRepo     repository.RoomBanRepository
+	invitationRepo  repository.InvitationRepository
+	applicationRepo repository.ApplicationRepository
+	inviteLinkRepo  repository.InviteLinkRepository
+	events          *systemEventWriter
+	permission      *permissionEvaluator
+	errWarpper      dtoError.ServiceErrorWarpper
+	logger          logger.Logger
+}
+
+func newModerationActions() *moderationActions {
+	return &moderationActions{
+		roomRepo:        repository.GetRoomRepository(),
+		messageRepo:     repository.GetMessageRepository(),
+		pinRepo:         repository.GetPinnedMessageRepository(),
+		roomBanRepo:     repository.GetRoomBanRepository(),
+		invitationRepo:  repository.GetInvitationRepository(),
+		applicationRepo: repository.GetApplicationRepository(),
+		inviteLinkRepo:  repository.GetInviteLinkRepository(),
+		events:          newSystemEventWriter(),
+		permission:      newPermissionEvaluator(),
+		errWarpper:      dtoError.GetServiceErrorWarpper(),
+		logger:          logger.NewLogger(),
+	}
+}
+
+// authorize is permission.check for the user taking the action. An operator acting on an
+// escalated report holds no role in the room, it is allowed with a nil member.
+func (a *moderationActions) authorize(ctx context.Context, roomID uint64, userID uint64, perm roomPermission, operator bool) (*model.RoomMember, bool, error) {
+	if operator {
+		return nil, true, nil
+	}
+	return a.permission.check(ctx, roomID, userID, perm)
+}
+
+// outranks is permission.outranks where a nil actor is an operator, who outranks every member.
+func (a *moderationActions) outranks(actor *model.RoomMember, target *model.RoomMember) bool {
+	return actor == nil || a.permission.outranks(actor, target)
+}
+
+// deleteMessage unpins and deletes a message, members may always delete their own.
+func (a *moderationActions) deleteMessage(ctx context.Context, req *dto.DeleteMessageRequest, operator bool) *dtoError.ServiceError {
+	requestId := common.GetUUID(ctx)
+	member, allowed, err := a.authorize(ctx, req.RoomID, req.UserID, permissionDeleteMessage, operator)
+	if err != nil {
+		a.logger.Error(requestId, "a.authorize", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if member == nil && !allowed {
+		return a.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	message, err := a.messageRepo.GetMessage(ctx, req.RoomID, req.MessageID)
+	if err != nil {
+		a.logger.Error(requestId, "a.messageRepo.GetMessage", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if message == nil {
+		return a.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	ownMessage := message.Kind != model.MessageKindSystem && message.UserID == req.UserID
+	if !ownMessage && !allowed {
+		return a.errWarpper.NewPermissionDeniedError(req.UserID, req.RoomID, permissionDeleteMessage.String())
+	}
+
+	err = a.pinRepo.DeleteMessagePins(ctx, req.MessageID)
+	if err != nil {
+		a.logger.Error(requestId, "a.pinRepo.DeleteMessagePins", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	}
+
+	ok, err := a.messageRepo.DeleteMessage(ctx, req.RoomID, req.MessageID)
+	if err != nil {
+		a.logger.Error(requestId, "a.messageRepo.DeleteMessage", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return a.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+	return nil
+}
+
+// mute sets or lifts the mute of a member, see MuteUser.
+func (a *moderationActions) mute(ctx context.Context, req *dto.MuteUserRequest, operator bool) (*dto.MuteUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	actor, allowed, err := a.authorize(ctx, req.RoomID, req.AdminUserID, permissionMute, operator)
+	if err != nil {
+		a.logger.Error(requestId, "a.authorize", req, err)
+		return nil, a.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, a.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionMute.String())
+	}
+
+	target, err := a.roomRepo.GetMember(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		a.logger.Error(requestId, "a.roomRepo.GetMember", req, err)
+		return nil, a.errWarpper.NewDBServiceError(err)
+	} else if target == nil {
+		return nil, a.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	} else if !a.outranks(actor, target) {
+		return nil, a.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionMute.String())
+	}
+
+	answer := dto.MuteUserResponse{}
+	var mutedUntil *time.Time
+	if req.DurationSecond > 0 {
+		until := time.Now().Add(time.Duration(req.DurationSecond) * time.Second)
+		mutedUntil = &until
+		answer.MutedUntil = common.TimeToUint64(until)
+	}
+
+	_, err = a.roomRepo.MuteUser(ctx, req.RoomID, req.UserID, mutedUntil)
+	if err != nil {
+		a.logger.Error(requestId, "a.roomRepo.MuteUser", req, err)
+		return nil, a.errWarpper.NewDBServiceError(err)
+	}
+	return &answer, nil
+}
+
+// ban is BanUser without the transaction, the ban event is written when the user was a member.
+func (a *moderationActions) ban(ctx context.Context, req *dto.BanUserRequest, operator bool) *dtoError.ServiceError {
+	requestId := common.GetUUID(ctx)
+	roomExist, err := a.roomRepo.RoomExist(ctx, req.RoomID)
+	if err != nil {
+		a.logger.Error(requestId, "a.roomRepo.RoomExist", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		return a.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	actor, allowed, err := a.authorize(ctx, req.RoomID, req.AdminUserID, permissionBan, operator)
+	if err != nil {
+		a.logger.Error(requestId, "a.authorize", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if !allowed || req.AdminUserID == req.UserID {
+		return a.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionBan.String())
+	}
+
+	target, err := a.roomRepo.GetMember(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		a.logger.Error(requestId, "a.roomRepo.GetMember", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	} else if target != nil && !a.outranks(actor, target) {
+		return a.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionBan.String())
+	}
+
+	ban := model.RoomBan{
+		RoomID:         req.RoomID,
+		UserID:         req.UserID,
+		BannedByUserID: req.AdminUserID,
+		Reason:         req.Reason,
+	}
+	if req.DurationSecond > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.DurationSecond) * time.Second)
+		ban.ExpiresAt = &expiresAt
+	}
+	err = a.roomBanRepo.BanUser(ctx, &ban)
+	if err != nil {
+		a.logger.Error(requestId, "a.roomBanRepo.BanUser", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	}
+
+	if target != nil {
+		_, err = a.roomRepo.DeleteUser(ctx, req.RoomID, req.UserID)
+		if err != nil {
+			a.logger.Error(requestId, "a.roomRepo.DeleteUser", req, err)
+			return a.errWarpper.NewDBServiceError(err)
+		}
+	}
+
+	_, err = a.invitationRepo.InviteNewUserRequestDelete(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		a.logger.Error(requestId, "a.invitationRepo.InviteNewUserRequestDelete", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	}
+
+	_, err = a.applicationRepo.RoomJoinApplyRequestDelete(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		a.logger.Error(requestId, "a.applicationRepo.RoomJoinApplyRequestDelete", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	}
+
+	// the dropped application counts as rejected
+	err = a.inviteLinkRepo.SettleInviteLinkUse(ctx, req.RoomID, req.UserID, false)
+	if err != nil {
+		a.logger.Error(requestId, "a.inviteLinkRepo.SettleInviteLinkUse", req, err)
+		return a.errWarpper.NewDBServiceError(err)
+	}
+
+	if target != nil {
+		err = a.events.write(ctx, req.RoomID, dto.SystemEvent{Type: model.SystemEventMemberBanned, ActorUserID: req.AdminUserID, TargetUserID: req.UserID})
+		if err != nil {
+			a.logger.Error(requestId, "a.events.write", req, err)
+			return a.errWarpper.NewDBServiceError(err)
+		}
+	}
+	return nil
+}
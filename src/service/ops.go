@@ -0,0 +1,462 @@
+package service
+
+import (
+	"ChatRoomAPI/src/cache"
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// OpsService is the platform operators' toolbox. Callers are checked to be platform admins by the
+// ops router, AccountStatus is what that check and the login filter run on.
+type OpsService interface {
+	AccountStatus(ctx context.Context, userID uint64) (*cache.AccountStatus, *dtoError.ServiceError)
+	SearchUsers(ctx context.Context, req *dto.SearchUsersRequest) (*dto.SearchUsersResponse, *dtoError.ServiceError)
+	SuspendUser(ctx context.Context, req *dto.SuspendUserRequest) (*dto.SuspendUserResponse, *dtoError.ServiceError)
+	UnsuspendUser(ctx context.Context, req *dto.UnsuspendUserRequest) (*dto.UnsuspendUserResponse, *dtoError.ServiceError)
+	PurgeRoom(ctx context.Context, req *dto.PurgeRoomRequest) (*dto.PurgeRoomResponse, *dtoError.ServiceError)
+	SetLegalHold(ctx context.Context, req *dto.SetLegalHoldRequest) (*dto.SetLegalHoldResponse, *dtoError.ServiceError)
+	AdjustWallet(ctx context.Context, req *dto.AdjustWalletRequest) (*dto.AdjustWalletResponse, *dtoError.ServiceError)
+	FetchAuditLogs(ctx context.Context, req *dto.FetchAuditLogsRequest) (*dto.FetchAuditLogsResponse, *dtoError.ServiceError)
+	FetchEscalatedReports(ctx context.Context, req *dto.FetchEscalatedReportsRequest) (*dto.FetchReportsResponse, *dtoError.ServiceError)
+}
+
+type opsServiceImpl struct {
+	userRepo     repository.AccountRepository
+	roomRepo     repository.RoomRepository
+	walletRepo   repository.WalletRepository
+	auditRepo    repository.AuditLogRepository
+	reportRepo   repository.ReportRepository
+	scheduleRepo repository.ScheduledJobRepository
+	statusCache  cache.AccountStatusCache
+	errWarpper   dtoError.ServiceErrorWarpper
+	logger       logger.Logger
+}
+
+var ops OpsService
+
+func init() {
+	ops = &opsServiceImpl{
+		userRepo:     repository.GetAccountRepository(),
+		roomRepo:     repository.GetRoomRepository(),
+		walletRepo:   repository.GetWalletRepository(),
+		auditRepo:    repository.GetAuditLogRepository(),
+		reportRepo:   repository.GetReportRepository(),
+		scheduleRepo: repository.GetScheduledJobRepository(),
+		statusCache:  cache.GetAccountStatusCache(),
+		errWarpper:   dtoError.GetServiceErrorWarpper(),
+		logger:       logger.NewLogger(),
+	}
+}
+
+func GetOpsService() OpsService {
+	return ops
+}
+
+// AccountStatus runs on every logged in request, so it reads the cache first. A broken cache falls
+// back to the database rather than locking everybody out.
+func (o *opsServiceImpl) AccountStatus(ctx context.Context, userID uint64) (*cache.AccountStatus, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	status, exist, err := o.statusCache.GetAccountStatus(ctx, userID)
+	if err != nil {
+		o.logger.Error(requestId, "o.statusCache.GetAccountStatus", userID, err)
+	} else if exist {
+		return status, nil
+	}
+
+	user, err := o.userRepo.AccountStatus(ctx, userID)
+	if err != nil {
+		o.logger.Error(requestId, "o.userRepo.AccountStatus", userID, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if user == nil {
+		return nil, o.errWarpper.NewUserNotExist(userID)
+	}
+
+	status = &cache.AccountStatus{PlatformRole: user.PlatformRole, Suspended: user.SuspendedAt != nil}
+	if err := o.statusCache.StoreAccountStatus(ctx, userID, *status); err != nil {
+		o.logger.Error(requestId, "o.statusCache.StoreAccountStatus", userID, err)
+	}
+	return status, nil
+}
+
+func (o *opsServiceImpl) SearchUsers(ctx context.Context, req *dto.SearchUsersRequest) (*dto.SearchUsersResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	users, err := o.userRepo.SearchUsers(ctx, strings.TrimSpace(req.Keyword), req.SuspendedOnly, skip, pageSize)
+	if err != nil {
+		o.logger.Error(requestId, "o.userRepo.SearchUsers", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.SearchUsersResponse{Users: make([]dto.OpsUser, len(users))}
+	for i, user := range users {
+		answer.Users[i].UserID = user.Id
+		answer.Users[i].Username = user.Username
+		answer.Users[i].Name = user.Name
+		answer.Users[i].Email = user.Email
+		answer.Users[i].PlatformRole = user.PlatformRole
+		if user.SuspendedAt != nil {
+			answer.Users[i].SuspendTime = common.TimeToUint64(*user.SuspendedAt)
+			answer.Users[i].SuspendReason = user.SuspendReason
+		}
+		answer.Users[i].CreateTime = common.TimeToUint64(user.CreatedAt)
+	}
+	return &answer, nil
+}
+
+// SuspendUser locks a user out of every endpoint behind the login filter. Suspending a suspended
+// user is a no-op and is not audited again.
+func (o *opsServiceImpl) SuspendUser(ctx context.Context, req *dto.SuspendUserRequest) (*dto.SuspendUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	if req.UserID == req.AdminUserID {
+		return nil, o.errWarpper.NewCannotSuspendSelfError(req.AdminUserID)
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	userExist, err := o.userRepo.CheckUserExist(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.CheckUserExist", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if !userExist {
+		tx.Rollback()
+		return nil, o.errWarpper.NewUserNotExist(req.UserID)
+	}
+
+	changed, err := o.userRepo.SuspendUser(txContext, req.UserID, req.Reason)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.SuspendUser", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if !changed {
+		tx.Rollback()
+		return &dto.SuspendUserResponse{}, nil
+	}
+
+	// messages the user scheduled would otherwise still be posted while suspended
+	_, err = o.scheduleRepo.CancelUserPendingJobs(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.scheduleRepo.CancelUserPendingJobs", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = o.auditRepo.AddAuditLog(txContext, &model.AuditLog{ActorUserID: req.AdminUserID, Action: model.AuditActionUserSuspended, TargetUserID: req.UserID, Detail: req.Reason})
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.auditRepo.AddAuditLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	user, err := o.userRepo.AccountStatus(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.AccountStatus", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		o.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, o.errWarpper.NewDBCommitServiceError(err)
+	}
+	o.storeAccountStatus(ctx, user)
+	return &dto.SuspendUserResponse{}, nil
+}
+
+func (o *opsServiceImpl) UnsuspendUser(ctx context.Context, req *dto.UnsuspendUserRequest) (*dto.UnsuspendUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	userExist, err := o.userRepo.CheckUserExist(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.CheckUserExist", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if !userExist {
+		tx.Rollback()
+		return nil, o.errWarpper.NewUserNotExist(req.UserID)
+	}
+
+	changed, err := o.userRepo.UnsuspendUser(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.UnsuspendUser", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if !changed {
+		tx.Rollback()
+		return &dto.UnsuspendUserResponse{}, nil
+	}
+
+	err = o.auditRepo.AddAuditLog(txContext, &model.AuditLog{ActorUserID: req.AdminUserID, Action: model.AuditActionUserUnsuspended, TargetUserID: req.UserID, Detail: req.Reason})
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.auditRepo.AddAuditLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	user, err := o.userRepo.AccountStatus(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.AccountStatus", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		o.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, o.errWarpper.NewDBCommitServiceError(err)
+	}
+	o.storeAccountStatus(ctx, user)
+	return &dto.UnsuspendUserResponse{}, nil
+}
+
+// storeAccountStatus writes the status committed by a change into the cache. Only dropping the key
+// lets a request that read the old row before the commit put it back for the whole expiry. If the
+// write fails the key is dropped, and the cache expiry bounds how stale it gets if that fails too.
+func (o *opsServiceImpl) storeAccountStatus(ctx context.Context, user *model.User) {
+	requestId := common.GetUUID(ctx)
+	status := cache.AccountStatus{PlatformRole: user.PlatformRole, Suspended: user.SuspendedAt != nil}
+	err := o.statusCache.StoreAccountStatus(ctx, user.Id, status)
+	if err == nil {
+		return
+	}
+	o.logger.Error(requestId, "o.statusCache.StoreAccountStatus", user.Id, err)
+	if err := o.statusCache.ClearAccountStatus(ctx, user.Id); err != nil {
+		o.logger.Error(requestId, "o.statusCache.ClearAccountStatus", user.Id, err)
+	}
+}
+
+// PurgeRoom hard deletes a room, live or in its restore window, with everything in it. The room
+// owner can not undo it, unlike DeleteRoom. A room under legal hold is refused, the hold has to be
+// lifted first.
+func (o *opsServiceImpl) PurgeRoom(ctx context.Context, req *dto.PurgeRoomRequest) (*dto.PurgeRoomResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	room, err := o.roomRepo.LockRoomForHold(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.roomRepo.LockRoomForHold", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if room == nil {
+		tx.Rollback()
+		return nil, o.errWarpper.NewRoomNotExistError(req.RoomID)
+	} else if room.LegalHold {
+		tx.Rollback()
+		return nil, o.errWarpper.NewRoomUnderHoldError(req.RoomID)
+	}
+
+	err = o.roomRepo.PurgeRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.roomRepo.PurgeRoom", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = o.auditRepo.AddAuditLog(txContext, &model.AuditLog{ActorUserID: req.AdminUserID, Action: model.AuditActionRoomPurged, RoomID: req.RoomID, Detail: req.Reason})
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.auditRepo.AddAuditLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		o.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, o.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.PurgeRoomResponse{}, nil
+}
+
+// SetLegalHold sets or lifts the legal hold of a room, neither retention nor a purge deletes anything
+// while it is set and the owner can not delete the room. It also reaches rooms that are already
+// deleted. Only platform admins can change it, every change is audited.
+func (o *opsServiceImpl) SetLegalHold(ctx context.Context, req *dto.SetLegalHoldRequest) (*dto.SetLegalHoldResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	// deleted rooms are included, a hold keeps them from being purged
+	room, err := o.roomRepo.LockRoomForHold(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.roomRepo.LockRoomForHold", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if room == nil {
+		tx.Rollback()
+		return nil, o.errWarpper.NewRoomNotExistError(req.RoomID)
+	} else if room.LegalHold == *req.LegalHold {
+		tx.Rollback()
+		return &dto.SetLegalHoldResponse{}, nil
+	}
+
+	err = o.roomRepo.UpdateLegalHold(txContext, req.RoomID, *req.LegalHold)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.roomRepo.UpdateLegalHold", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	action := model.AuditActionLegalHoldLifted
+	if *req.LegalHold {
+		action = model.AuditActionLegalHoldSet
+	}
+	err = o.auditRepo.AddAuditLog(txContext, &model.AuditLog{ActorUserID: req.AdminUserID, Action: action, RoomID: req.RoomID, Detail: req.Reason})
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.auditRepo.AddAuditLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		o.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, o.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.SetLegalHoldResponse{}, nil
+}
+
+// AdjustWallet credits or debits a wallet outside the charge and purchase flows, the wallet log
+// and the audit log both carry the reason.
+func (o *opsServiceImpl) AdjustWallet(ctx context.Context, req *dto.AdjustWalletRequest) (*dto.AdjustWalletResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	userExist, err := o.userRepo.CheckUserExist(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.userRepo.CheckUserExist", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	} else if !userExist {
+		tx.Rollback()
+		return nil, o.errWarpper.NewUserNotExist(req.UserID)
+	}
+
+	detail := fmt.Sprintf("adjusted by user %d: %s", req.AdminUserID, req.Reason)
+	if req.Amount > 0 {
+		err = o.walletRepo.WalletInit(txContext, req.UserID)
+		if err != nil {
+			tx.Rollback()
+			o.logger.Error(requestId, "o.walletRepo.WalletInit", req, err)
+			return nil, o.errWarpper.NewDBServiceError(err)
+		}
+		err = o.walletRepo.Charge(txContext, req.UserID, uint32(req.Amount))
+		if err != nil {
+			tx.Rollback()
+			o.logger.Error(requestId, "o.walletRepo.Charge", req, err)
+			return nil, o.errWarpper.NewDBServiceError(err)
+		}
+		_, err = o.walletRepo.WriteLog(txContext, req.UserID, 0, uint32(req.Amount), detail)
+	} else {
+		_, ok, err := o.walletRepo.Cost(txContext, req.UserID, uint32(-req.Amount))
+		if err != nil {
+			tx.Rollback()
+			o.logger.Error(requestId, "o.walletRepo.Cost", req, err)
+			return nil, o.errWarpper.NewDBServiceError(err)
+		} else if !ok {
+			tx.Rollback()
+			return nil, o.errWarpper.NewUserMoneyNotEnoughError(req.UserID)
+		}
+		_, err = o.walletRepo.WriteLog(txContext, req.UserID, 1, uint32(-req.Amount), detail)
+	}
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.walletRepo.WriteLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	entry := model.AuditLog{ActorUserID: req.AdminUserID, Action: model.AuditActionWalletAdjusted, TargetUserID: req.UserID, Detail: fmt.Sprintf("%+d: %s", req.Amount, req.Reason)}
+	err = o.auditRepo.AddAuditLog(txContext, &entry)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.auditRepo.AddAuditLog", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	wallet, _, err := o.walletRepo.GetState(txContext, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		o.logger.Error(requestId, "o.walletRepo.GetState", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		o.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, o.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.AdjustWalletResponse{Money: wallet.Money}, nil
+}
+
+func (o *opsServiceImpl) FetchAuditLogs(ctx context.Context, req *dto.FetchAuditLogsRequest) (*dto.FetchAuditLogsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	logs, err := o.auditRepo.FetchAuditLogs(ctx, req.RoomID, req.ActorUserID, req.TargetUserID, req.Action, skip, pageSize)
+	if err != nil {
+		o.logger.Error(requestId, "o.auditRepo.FetchAuditLogs", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchAuditLogsResponse{Logs: make([]dto.AuditLog, len(logs))}
+	for i, log := range logs {
+		answer.Logs[i] = dto.AuditLog{
+			AuditLogID:   log.Id,
+			ActorUserID:  log.ActorUserID,
+			Action:       log.Action,
+			RoomID:       log.RoomID,
+			TargetUserID: log.TargetUserID,
+			MessageID:    log.MessageID,
+			ReportID:     log.ReportID,
+			Detail:       log.Detail,
+			CreateTime:   common.TimeToUint64(log.CreatedAt),
+		}
+	}
+	return &answer, nil
+}
+
+// FetchEscalatedReports is the operators' view of the reports members escalated, across rooms.
+func (o *opsServiceImpl) FetchEscalatedReports(ctx context.Context, req *dto.FetchEscalatedReportsRequest) (*dto.FetchReportsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	o.logger.Info(requestId, "start", req, nil)
+	defer func() { o.logger.Info(requestId, "end", req, nil) }()
+
+	status := req.Status
+	if status == "" {
+		status = model.ReportStatusOpen
+	}
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	reports, err := o.reportRepo.FetchEscalatedReports(ctx, status, skip, pageSize)
+	if err != nil {
+		o.logger.Error(requestId, "o.reportRepo.FetchEscalatedReports", req, err)
+		return nil, o.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchReportsResponse{Reports: make([]dto.Report, len(reports))}
+	for i, report := range reports {
+		answer.Reports[i] = reportResponse(report)
+	}
+	return &answer, nil
+}
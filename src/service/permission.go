@@ -0,0 +1,118 @@
+package service
+
+import (
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"strings"
+)
+
+type roomPermission uint32
+
+const (
+	permissionInvite roomPermission = 1 << iota
+	permissionApprove
+	permissionKick
+	permissionBan
+	permissionMute
+	permissionDeleteMessage
+	permissionEditRoom
+	permissionPin
+	permissionAssignRole
+	permissionTransferOwnership
+	permissionDeleteRoom
+	permissionArchiveRoom
+	permissionPost
+	permissionBroadcast
+	permissionExport
+	permissionModerate
+)
+
+var roomPermissionNames = map[roomPermission]string{
+	permissionInvite:            "invite",
+	permissionApprove:           "approve",
+	permissionKick:              "kick",
+	permissionBan:               "ban",
+	permissionMute:              "mute",
+	permissionDeleteMessage:     "delete_message",
+	permissionEditRoom:          "edit_room",
+	permissionPin:               "pin",
+	permissionAssignRole:        "assign_role",
+	permissionTransferOwnership: "transfer_ownership",
+	permissionDeleteRoom:        "delete_room",
+	permissionArchiveRoom:       "archive_room",
+	permissionPost:              "post",
+	permissionBroadcast:         "broadcast",
+	permissionExport:            "export",
+	permissionModerate:          "moderate",
+}
+
+func (p roomPermission) String() string {
+	names := []string{}
+	for bit := permissionInvite; bit <= permissionModerate; bit <<= 1 {
+		if p&bit != 0 {
+			names = append(names, roomPermissionNames[bit])
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+var rolePermissions = map[string]roomPermission{
+	model.RoomRoleOwner: permissionInvite | permissionApprove | permissionKick | permissionBan | permissionMute |
+		permissionDeleteMessage | permissionEditRoom | permissionPin | permissionAssignRole | permissionTransferOwnership | permissionDeleteRoom | permissionArchiveRoom |
+		permissionPost | permissionBroadcast | permissionExport | permissionModerate,
+	model.RoomRoleModerator: permissionInvite | permissionApprove | permissionKick | permissionBan | permissionMute |
+		permissionDeleteMessage | permissionPin | permissionPost | permissionBroadcast | permissionExport | permissionModerate,
+	model.RoomRoleMember:   permissionPost,
+	model.RoomRoleReadOnly: 0,
+}
+
+// postPermission is what a member needs to send a message in a room of the given mode.
+func postPermission(mode string) roomPermission {
+	if mode == model.RoomModeAnnouncement {
+		return permissionBroadcast
+	}
+	return permissionPost
+}
+
+// roleRank orders roles so that nobody can act on a member of the same or a higher role.
+var roleRank = map[string]int{
+	model.RoomRoleOwner:     3,
+	model.RoomRoleModerator: 2,
+	model.RoomRoleMember:    1,
+	model.RoomRoleReadOnly:  0,
+}
+
+// assignableRoles are the roles AssignRole may hand out, ownership moves through AdminChange only.
+var assignableRoles = map[string]bool{
+	model.RoomRoleModerator: true,
+	model.RoomRoleMember:    true,
+	model.RoomRoleReadOnly:  true,
+}
+
+// permissionEvaluator is the single place that decides what a room member may do.
+type permissionEvaluator struct {
+	roomRepo repository.RoomRepository
+}
+
+func newPermissionEvaluator() *permissionEvaluator {
+	return &permissionEvaluator{roomRepo: repository.GetRoomRepository()}
+}
+
+// check loads the membership of userID and reports whether its role grants every bit of perm.
+// member is nil when the user is not in the room.
+func (p *permissionEvaluator) check(ctx context.Context, roomID uint64, userID uint64, perm roomPermission) (*model.RoomMember, bool, error) {
+	member, err := p.roomRepo.GetMember(ctx, roomID, userID)
+	if err != nil || member == nil {
+		return nil, false, err
+	}
+	return member, p.granted(member, perm), nil
+}
+
+func (p *permissionEvaluator) granted(member *model.RoomMember, perm roomPermission) bool {
+	return rolePermissions[member.Role]&perm == perm
+}
+
+func (p *permissionEvaluator) outranks(actor *model.RoomMember, target *model.RoomMember) bool {
+	return roleRank[actor.Role] > roleRank[target.Role]
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CreatePoll posts a poll message, it goes through the same checks as a text message.
+func (m *messageServiceImpl) CreatePoll(ctx context.Context, req *dto.CreatePollRequest) (*dto.CreatePollResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	payload := model.PollPayload{
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+	}
+	if req.CloseTime != 0 {
+		closeTime := common.Uint64ToTime(req.CloseTime)
+		if !closeTime.After(time.Now()) {
+			return nil, m.errWarpper.NewParseFormatFailedServiceError(nil, "close_time must be in the future")
+		}
+		payload.CloseTime = &closeTime
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomInfo, serviceErr := m.checkPost(ctx, txContext, req.RoomID, req.UserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	question, options, rejected, err := m.filters.runPoll(ctx, roomInfo, req.UserID, req.Question, req.Options)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.filters.runPoll", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
+	}
+	payload.Options = options
+	data, err := json.Marshal(payload)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "json.Marshal", req, err)
+		return nil, m.errWarpper.NewParseFormatFailedServiceError(err, "poll can not be encoded")
+	}
+
+	serviceErr = m.acquirePost(ctx, roomInfo, req.UserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	rejected, err = m.filters.checkFlood(ctx, roomInfo, req.UserID, question)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.filters.checkFlood", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if rejected != nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageRejectedError(req.RoomID, rejected.filter, rejected.reason)
+	}
+
+	message, err := m.messageRepo.AddPollMessage(txContext, req.RoomID, req.UserID, question, string(data))
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.messageRepo.AddPollMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		m.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, m.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.CreatePollResponse{
+		ID:        message.ID,
+		CreatedAt: common.TimeToUint64(message.CreatedAt),
+	}, nil
+}
+
+// VotePoll replaces the vote of a room member on an open poll and returns the new result.
+func (m *messageServiceImpl) VotePoll(ctx context.Context, req *dto.VotePollRequest) (*dto.VotePollResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	m.logger.Info(requestId, "start", req, nil)
+	defer func() { m.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	inRoom, err := m.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.roomRepo.CheckUserInRoom", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		tx.Rollback()
+		return nil, m.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	archived, err := roomIsArchived(txContext, m.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, m.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	// two votes of the same user would each delete what the other has not committed yet and both
+	// insert, leaving a single choice poll with two options
+	exist, err := m.messageRepo.LockMessage(txContext, req.RoomID, req.MessageID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.messageRepo.LockMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if !exist {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	message, err := m.messageRepo.GetMessage(txContext, req.RoomID, req.MessageID)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.messageRepo.GetMessage", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	} else if message == nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	payload := parsePollPayload(message)
+	if payload == nil {
+		tx.Rollback()
+		return nil, m.errWarpper.NewMessageNotPollError(req.MessageID, req.RoomID)
+	} else if payload.Closed(time.Now()) {
+		tx.Rollback()
+		return nil, m.errWarpper.NewPollClosedError(req.MessageID)
+	}
+
+	options, reason := validPollVote(payload, req.Options)
+	if reason != "" {
+		tx.Rollback()
+		return nil, m.errWarpper.NewInvalidPollVoteError(req.MessageID, reason)
+	}
+
+	err = m.pollRepo.ReplaceVotes(txContext, req.MessageID, req.UserID, options)
+	if err != nil {
+		tx.Rollback()
+		m.logger.Error(requestId, "m.pollRepo.ReplaceVotes", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		m.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, m.errWarpper.NewDBCommitServiceError(err)
+	}
+
+	polls, err := m.pollResults(ctx, req.UserID, []*model.Message{message})
+	if err != nil {
+		m.logger.Error(requestId, "m.pollResults", req, err)
+		return nil, m.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.VotePollResponse{Poll: *polls[message.ID]}, nil
+}
+
+// pollResults tallies the votes of the poll messages among messages, keyed by message id.
+func (m *messageServiceImpl) pollResults(ctx context.Context, viewerID uint64, messages []*model.Message) (map[uint64]*dto.Poll, error) {
+	polls := map[uint64]*dto.Poll{}
+	ids := []uint64{}
+	now := time.Now()
+	for _, message := range messages {
+		payload := parsePollPayload(message)
+		if payload == nil {
+			continue
+		}
+		poll := &dto.Poll{
+			Options:        make([]dto.PollOption, len(payload.Options)),
+			MultipleChoice: payload.MultipleChoice,
+			Anonymous:      payload.Anonymous,
+			Closed:         payload.Closed(now),
+			MyVotes:        []int{},
+		}
+		for i, text := range payload.Options {
+			poll.Options[i].Text = text
+		}
+		if payload.CloseTime != nil {
+			poll.CloseTime = common.TimeToUint64(*payload.CloseTime)
+		}
+		polls[message.ID] = poll
+		ids = append(ids, message.ID)
+	}
+	if len(ids) == 0 {
+		return polls, nil
+	}
+
+	votes, err := m.pollRepo.FetchVotes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	voters := map[uint64]map[uint64]struct{}{}
+	for _, vote := range votes {
+		poll := polls[vote.MessageID]
+		if vote.Option < 0 || vote.Option >= len(poll.Options) {
+			continue
+		}
+		option := &poll.Options[vote.Option]
+		option.VoteCount++
+		if !poll.Anonymous {
+			option.VoterIDs = append(option.VoterIDs, vote.UserID)
+		}
+		if vote.UserID == viewerID {
+			poll.MyVotes = append(poll.MyVotes, vote.Option)
+		}
+		if voters[vote.MessageID] == nil {
+			voters[vote.MessageID] = map[uint64]struct{}{}
+		}
+		voters[vote.MessageID][vote.UserID] = struct{}{}
+	}
+	for id, users := range voters {
+		polls[id].VoterCount = len(users)
+	}
+	return polls, nil
+}
+
+// parsePollPayload decodes the payload of a poll message, nil for other messages or bad payloads.
+func parsePollPayload(message *model.Message) *model.PollPayload {
+	if message.Kind != model.MessageKindPoll {
+		return nil
+	}
+	payload := model.PollPayload{}
+	if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+		return nil
+	}
+	return &payload
+}
+
+// validPollVote drops repeated options and returns a reason when the vote does not fit the poll.
+func validPollVote(payload *model.PollPayload, options []int) ([]int, string) {
+	seen := map[int]struct{}{}
+	unique := make([]int, 0, len(options))
+	for _, option := range options {
+		if option < 0 || option >= len(payload.Options) {
+			return nil, fmt.Sprintf("option %d does not exist", option)
+		}
+		if _, ok := seen[option]; ok {
+			continue
+		}
+		seen[option] = struct{}{}
+		unique = append(unique, option)
+	}
+	if !payload.MultipleChoice && len(unique) > 1 {
+		return nil, "the poll allows a single choice"
+	}
+	return unique, ""
+}
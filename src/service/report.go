@@ -0,0 +1,499 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"fmt"
+)
+
+type ReportService interface {
+	FileReport(ctx context.Context, req *dto.FileReportRequest) (*dto.FileReportResponse, *dtoError.ServiceError)
+	FetchReports(ctx context.Context, req *dto.FetchReportsRequest) (*dto.FetchReportsResponse, *dtoError.ServiceError)
+	ClaimReport(ctx context.Context, req *dto.ClaimReportRequest) (*dto.ClaimReportResponse, *dtoError.ServiceError)
+	ResolveReport(ctx context.Context, req *dto.ResolveReportRequest) (*dto.ResolveReportResponse, *dtoError.ServiceError)
+	DismissReport(ctx context.Context, req *dto.DismissReportRequest) (*dto.DismissReportResponse, *dtoError.ServiceError)
+	ClaimEscalatedReport(ctx context.Context, req *dto.ClaimReportRequest) (*dto.ClaimReportResponse, *dtoError.ServiceError)
+	ResolveEscalatedReport(ctx context.Context, req *dto.ResolveReportRequest) (*dto.ResolveReportResponse, *dtoError.ServiceError)
+	DismissEscalatedReport(ctx context.Context, req *dto.DismissReportRequest) (*dto.DismissReportResponse, *dtoError.ServiceError)
+}
+
+type reportServiceImpl struct {
+	reportRepo  repository.ReportRepository
+	auditRepo   repository.AuditLogRepository
+	roomRepo    repository.RoomRepository
+	messageRepo repository.MessageRepository
+	events      *systemEventWriter
+	permission  *permissionEvaluator
+	moderation  *moderationActions
+	errWarpper  dtoError.ServiceErrorWarpper
+	logger      logger.Logger
+}
+
+var report ReportService
+
+func init() {
+	report = &reportServiceImpl{
+		reportRepo:  repository.GetReportRepository(),
+		auditRepo:   repository.GetAuditLogRepository(),
+		roomRepo:    repository.GetRoomRepository(),
+		messageRepo: repository.GetMessageRepository(),
+		events:      newSystemEventWriter(),
+		permission:  newPermissionEvaluator(),
+		moderation:  newModerationActions(),
+		errWarpper:  dtoError.GetServiceErrorWarpper(),
+		logger:      logger.NewLogger(),
+	}
+}
+
+func GetReportService() ReportService {
+	return report
+}
+
+// FileReport lets a member report a message or another member of the room. Reporting the same
+// thing again while the first report is pending returns the pending one.
+func (r *reportServiceImpl) FileReport(ctx context.Context, req *dto.FileReportRequest) (*dto.FileReportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	member, err := r.roomRepo.GetMember(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.GetMember", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if member == nil {
+		return nil, r.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	entry := model.Report{
+		RoomID:       req.RoomID,
+		ReporterID:   req.UserID,
+		TargetUserID: req.TargetUserID,
+		MessageID:    req.MessageID,
+		Reason:       req.Reason,
+		Detail:       req.Detail,
+		Escalated:    req.Escalate,
+	}
+	if req.MessageID != 0 {
+		message, err := r.messageRepo.GetMessage(ctx, req.RoomID, req.MessageID)
+		if err != nil {
+			r.logger.Error(requestId, "r.messageRepo.GetMessage", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if message == nil {
+			return nil, r.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+		} else if message.Kind == model.MessageKindSystem {
+			return nil, r.errWarpper.NewInvalidReportError("system messages can not be reported")
+		} else if req.TargetUserID != 0 && req.TargetUserID != message.UserID {
+			return nil, r.errWarpper.NewInvalidReportError("target_user_id is not the author of the message")
+		}
+		entry.TargetUserID = message.UserID
+		entry.Content = message.Content
+	}
+	if entry.TargetUserID == 0 {
+		return nil, r.errWarpper.NewInvalidReportError("either message_id or target_user_id is required")
+	} else if entry.TargetUserID == req.UserID {
+		return nil, r.errWarpper.NewInvalidReportError("users can not report themselves")
+	} else if req.MessageID == 0 {
+		serviceErr := r.checkTarget(ctx, req.RoomID, entry.TargetUserID)
+		if serviceErr != nil {
+			return nil, serviceErr
+		}
+	}
+
+	pending, err := r.reportRepo.FindPendingReport(ctx, req.UserID, req.RoomID, entry.TargetUserID, entry.MessageID)
+	if err != nil {
+		r.logger.Error(requestId, "r.reportRepo.FindPendingReport", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if pending != nil {
+		return &dto.FileReportResponse{ReportID: pending.Id}, nil
+	}
+
+	err = r.reportRepo.CreateReport(ctx, &entry)
+	if err != nil {
+		r.logger.Error(requestId, "r.reportRepo.CreateReport", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.FileReportResponse{ReportID: entry.Id}, nil
+}
+
+// checkTarget makes sure a user reported on their own is or was in the room, as a member or as
+// the author of a message there.
+func (r *reportServiceImpl) checkTarget(ctx context.Context, roomID uint64, targetUserID uint64) *dtoError.ServiceError {
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"room_id": roomID, "target_user_id": targetUserID}
+
+	target, err := r.roomRepo.GetMember(ctx, roomID, targetUserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.GetMember", data, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if target != nil {
+		return nil
+	}
+
+	posted, err := r.messageRepo.UserPosted(ctx, roomID, targetUserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.messageRepo.UserPosted", data, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if !posted {
+		return r.errWarpper.NewUserNotInRoomError(targetUserID, roomID)
+	}
+	return nil
+}
+
+// FetchReports is the moderation queue of a room.
+func (r *reportServiceImpl) FetchReports(ctx context.Context, req *dto.FetchReportsRequest) (*dto.FetchReportsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionModerate)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionModerate.String())
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.ReportStatusOpen
+	}
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	reports, err := r.reportRepo.FetchReports(ctx, req.RoomID, status, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.reportRepo.FetchReports", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchReportsResponse{Reports: make([]dto.Report, len(reports))}
+	for i, report := range reports {
+		answer.Reports[i] = reportResponse(report)
+	}
+	return &answer, nil
+}
+
+// ClaimReport takes a report off the queue for the moderator, so two moderators do not act on the
+// same report.
+func (r *reportServiceImpl) ClaimReport(ctx context.Context, req *dto.ClaimReportRequest) (*dto.ClaimReportResponse, *dtoError.ServiceError) {
+	return r.claimReport(ctx, req, false)
+}
+
+// ClaimEscalatedReport is ClaimReport for a platform operator, who needs no role in the room but
+// may only take escalated reports.
+func (r *reportServiceImpl) ClaimEscalatedReport(ctx context.Context, req *dto.ClaimReportRequest) (*dto.ClaimReportResponse, *dtoError.ServiceError) {
+	return r.claimReport(ctx, req, true)
+}
+
+func (r *reportServiceImpl) claimReport(ctx context.Context, req *dto.ClaimReportRequest, operator bool) (*dto.ClaimReportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	report, serviceErr := r.moderatedReport(ctx, req.ReportID, req.AdminUserID, operator)
+	if serviceErr != nil {
+		return nil, serviceErr
+	} else if report.Status == model.ReportStatusClaimed && report.ClaimedByUserID == req.AdminUserID {
+		return &dto.ClaimReportResponse{}, nil
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	serviceErr = r.claim(ctx, txContext, report, req.AdminUserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	err := r.audit(txContext, report, req.AdminUserID, model.AuditActionReportClaimed, "")
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.audit", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.ClaimReportResponse{}, nil
+}
+
+// ResolveReport claims the report, takes the action on the reported message or user and closes
+// the report, all in one transaction with their audit logs. The action runs the same steps as the
+// moderator endpoint, so its permission and rank checks apply. When it fails nothing is changed.
+func (r *reportServiceImpl) ResolveReport(ctx context.Context, req *dto.ResolveReportRequest) (*dto.ResolveReportResponse, *dtoError.ServiceError) {
+	return r.resolveReport(ctx, req, false)
+}
+
+// ResolveEscalatedReport is ResolveReport for a platform operator. The operator outranks every
+// member of the room, the action still needs its target to be there.
+func (r *reportServiceImpl) ResolveEscalatedReport(ctx context.Context, req *dto.ResolveReportRequest) (*dto.ResolveReportResponse, *dtoError.ServiceError) {
+	return r.resolveReport(ctx, req, true)
+}
+
+func (r *reportServiceImpl) resolveReport(ctx context.Context, req *dto.ResolveReportRequest, operator bool) (*dto.ResolveReportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	report, serviceErr := r.moderatedReport(ctx, req.ReportID, req.AdminUserID, operator)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+	if req.Action == model.ReportActionDeleteMessage && report.MessageID == 0 {
+		return nil, r.errWarpper.NewInvalidReportError("the report is not about a message")
+	} else if req.Action == model.ReportActionMute && req.DurationSecond == 0 {
+		return nil, r.errWarpper.NewInvalidReportError("a mute needs duration_second")
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	serviceErr = r.claim(ctx, txContext, report, req.AdminUserID)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+	if report.Status == model.ReportStatusOpen {
+		err := r.audit(txContext, report, req.AdminUserID, model.AuditActionReportClaimed, "")
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.audit", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+	}
+
+	switch req.Action {
+	case model.ReportActionDeleteMessage:
+		serviceErr = r.moderation.deleteMessage(txContext, &dto.DeleteMessageRequest{RoomID: report.RoomID, MessageID: report.MessageID, UserID: req.AdminUserID}, operator)
+		// somebody deleting the message first still leaves it deleted
+		if serviceErr != nil && serviceErr.ErrorCode == dtoError.MessageNotExist {
+			serviceErr = nil
+		}
+	case model.ReportActionMute:
+		_, serviceErr = r.moderation.mute(txContext, &dto.MuteUserRequest{
+			RoomID:         report.RoomID,
+			AdminUserID:    req.AdminUserID,
+			UserID:         report.TargetUserID,
+			DurationSecond: req.DurationSecond,
+		}, operator)
+	case model.ReportActionBan:
+		serviceErr = r.moderation.ban(txContext, &dto.BanUserRequest{
+			RoomID:         report.RoomID,
+			AdminUserID:    req.AdminUserID,
+			UserID:         report.TargetUserID,
+			Reason:         fmt.Sprintf("report %d: %s", report.Id, report.Reason),
+			DurationSecond: req.DurationSecond,
+		}, operator)
+	case model.ReportActionWarn:
+		serviceErr = r.warn(ctx, txContext, report, req.AdminUserID, operator)
+	}
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	serviceErr = r.close(ctx, txContext, report, req.AdminUserID, model.ReportStatusResolved, req.Action, req.Note)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.ResolveReportResponse{}, nil
+}
+
+// DismissReport closes a report without acting on it.
+func (r *reportServiceImpl) DismissReport(ctx context.Context, req *dto.DismissReportRequest) (*dto.DismissReportResponse, *dtoError.ServiceError) {
+	return r.dismissReport(ctx, req, false)
+}
+
+// DismissEscalatedReport is DismissReport for a platform operator.
+func (r *reportServiceImpl) DismissEscalatedReport(ctx context.Context, req *dto.DismissReportRequest) (*dto.DismissReportResponse, *dtoError.ServiceError) {
+	return r.dismissReport(ctx, req, true)
+}
+
+func (r *reportServiceImpl) dismissReport(ctx context.Context, req *dto.DismissReportRequest, operator bool) (*dto.DismissReportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	report, serviceErr := r.moderatedReport(ctx, req.ReportID, req.AdminUserID, operator)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	serviceErr = r.close(ctx, txContext, report, req.AdminUserID, model.ReportStatusDismissed, "", req.Note)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.DismissReportResponse{}, nil
+}
+
+// moderatedReport loads a report the user may moderate that nobody decided on yet. Operators see
+// only the escalated reports, any other one does not exist for them.
+func (r *reportServiceImpl) moderatedReport(ctx context.Context, reportID uint64, userID uint64, operator bool) (*model.Report, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"report_id": reportID, "user_id": userID}
+
+	report, err := r.reportRepo.GetReport(ctx, reportID)
+	if err != nil {
+		r.logger.Error(requestId, "r.reportRepo.GetReport", data, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if report == nil {
+		return nil, r.errWarpper.NewReportNotExistError(reportID)
+	} else if operator {
+		if !report.Escalated {
+			return nil, r.errWarpper.NewReportNotExistError(reportID)
+		}
+		return report, r.unavailable(report, userID)
+	}
+
+	_, allowed, err := r.permission.check(ctx, report.RoomID, userID, permissionModerate)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", data, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(userID, report.RoomID, permissionModerate.String())
+	}
+	return report, r.unavailable(report, userID)
+}
+
+// unavailable is the error for a report the user can no longer claim or close, nil when they can.
+func (r *reportServiceImpl) unavailable(report *model.Report, userID uint64) *dtoError.ServiceError {
+	switch {
+	case report.Status == model.ReportStatusResolved || report.Status == model.ReportStatusDismissed:
+		return r.errWarpper.NewReportClosedError(report.Id, report.Status)
+	case report.Status == model.ReportStatusClaimed && report.ClaimedByUserID != userID:
+		return r.errWarpper.NewReportClaimedError(report.Id, report.ClaimedByUserID)
+	}
+	return nil
+}
+
+func (r *reportServiceImpl) claim(ctx context.Context, txContext context.Context, report *model.Report, userID uint64) *dtoError.ServiceError {
+	ok, err := r.reportRepo.ClaimReport(txContext, report.Id, userID)
+	if err != nil {
+		r.logger.Error(common.GetUUID(ctx), "r.reportRepo.ClaimReport", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return r.lostRace(ctx, txContext, report.Id, userID)
+	}
+	return nil
+}
+
+// close resolves or dismisses the report and records the decision in the audit log.
+func (r *reportServiceImpl) close(ctx context.Context, txContext context.Context, report *model.Report, userID uint64, status string, action string, note string) *dtoError.ServiceError {
+	requestId := common.GetUUID(ctx)
+	ok, err := r.reportRepo.CloseReport(txContext, report.Id, userID, status, action, note)
+	if err != nil {
+		r.logger.Error(requestId, "r.reportRepo.CloseReport", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return r.lostRace(ctx, txContext, report.Id, userID)
+	}
+
+	auditAction := model.AuditActionReportResolved
+	if status == model.ReportStatusDismissed {
+		auditAction = model.AuditActionReportDismissed
+	}
+	err = r.audit(txContext, report, userID, auditAction, action)
+	if err != nil {
+		r.logger.Error(requestId, "r.audit", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	}
+	return nil
+}
+
+// lostRace reloads a report another moderator changed since it was read, for the right error.
+func (r *reportServiceImpl) lostRace(ctx context.Context, txContext context.Context, reportID uint64, userID uint64) *dtoError.ServiceError {
+	report, err := r.reportRepo.GetReport(txContext, reportID)
+	if err != nil {
+		r.logger.Error(common.GetUUID(ctx), "r.reportRepo.GetReport", reportID, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if report == nil {
+		return r.errWarpper.NewReportNotExistError(reportID)
+	} else if serviceErr := r.unavailable(report, userID); serviceErr != nil {
+		return serviceErr
+	}
+	return r.errWarpper.NewDBNoAffectedServiceError()
+}
+
+// warn posts a warning to the room timeline, with the same rank rule as a mute or a ban.
+func (r *reportServiceImpl) warn(ctx context.Context, txContext context.Context, report *model.Report, userID uint64, operator bool) *dtoError.ServiceError {
+	requestId := common.GetUUID(ctx)
+	actor, allowed, err := r.moderation.authorize(txContext, report.RoomID, userID, permissionModerate, operator)
+	if err != nil {
+		r.logger.Error(requestId, "r.moderation.authorize", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	}
+	target, err := r.roomRepo.GetMember(txContext, report.RoomID, report.TargetUserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.GetMember", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	} else if target == nil {
+		return r.errWarpper.NewUserNotInRoomError(report.TargetUserID, report.RoomID)
+	} else if !allowed || !r.moderation.outranks(actor, target) {
+		return r.errWarpper.NewPermissionDeniedError(userID, report.RoomID, permissionModerate.String())
+	}
+
+	event := dto.SystemEvent{Type: model.SystemEventMemberWarned, ActorUserID: userID, TargetUserID: report.TargetUserID, MessageID: report.MessageID}
+	err = r.events.write(txContext, report.RoomID, event)
+	if err != nil {
+		r.logger.Error(requestId, "r.events.write", report, err)
+		return r.errWarpper.NewDBServiceError(err)
+	}
+	return nil
+}
+
+func (r *reportServiceImpl) audit(txContext context.Context, report *model.Report, userID uint64, action string, detail string) error {
+	return r.auditRepo.AddAuditLog(txContext, &model.AuditLog{
+		ActorUserID:  userID,
+		Action:       action,
+		RoomID:       report.RoomID,
+		TargetUserID: report.TargetUserID,
+		MessageID:    report.MessageID,
+		ReportID:     report.Id,
+		Detail:       detail,
+	})
+}
+
+func reportResponse(report *model.Report) dto.Report {
+	answer := dto.Report{
+		ReportID:         report.Id,
+		RoomID:           report.RoomID,
+		ReporterID:       report.ReporterID,
+		TargetUserID:     report.TargetUserID,
+		MessageID:        report.MessageID,
+		Content:          report.Content,
+		Reason:           report.Reason,
+		Detail:           report.Detail,
+		Escalated:        report.Escalated,
+		Status:           report.Status,
+		ClaimedByUserID:  report.ClaimedByUserID,
+		ResolvedByUserID: report.ResolvedByUserID,
+		Action:           report.Action,
+		Note:             report.Note,
+		CreateTime:       common.TimeToUint64(report.CreatedAt),
+	}
+	if report.ClaimedAt != nil {
+		answer.ClaimTime = common.TimeToUint64(*report.ClaimedAt)
+	}
+	if report.ResolvedAt != nil {
+		answer.ResolveTime = common.TimeToUint64(*report.ResolvedAt)
+	}
+	return answer
+}
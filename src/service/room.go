@@ -5,30 +5,46 @@ import (
 	"ChatRoomAPI/src/dto"
 	"ChatRoomAPI/src/dtoError"
 	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
 	"ChatRoomAPI/src/repository"
 	"context"
+	"strings"
+	"time"
 )
 
 type RoomService interface {
 	CreateRoom(ctx context.Context, req *dto.CreateRoomRequest) (*dto.CreateRoomResponse, *dtoError.ServiceError)
+	OpenDirectRoom(ctx context.Context, req *dto.OpenDirectRoomRequest) (*dto.OpenDirectRoomResponse, *dtoError.ServiceError)
 	GetAvailbleRooms(ctx context.Context, req *dto.GetAvailbleRoomsRequest) (*dto.GetAvailbleRoomsResponse, *dtoError.ServiceError)
 	ReadRoomInfo(ctx context.Context, req *dto.ReadRoomInfoRequest) (*dto.ReadRoomInfoResponse, *dtoError.ServiceError)
 	DeleteRoom(ctx context.Context, req *dto.DeleteRoomRequest) (*dto.DeleteRoomResponse, *dtoError.ServiceError)
+	SearchRoomDirectory(ctx context.Context, req *dto.SearchRoomDirectoryRequest) (*dto.SearchRoomDirectoryResponse, *dtoError.ServiceError)
+	ArchiveRoom(ctx context.Context, req *dto.ArchiveRoomRequest) (*dto.ArchiveRoomResponse, *dtoError.ServiceError)
+	FetchDeletedRooms(ctx context.Context, req *dto.FetchDeletedRoomsRequest) (*dto.FetchDeletedRoomsResponse, *dtoError.ServiceError)
+	RestoreRoom(ctx context.Context, req *dto.RestoreRoomRequest) (*dto.RestoreRoomResponse, *dtoError.ServiceError)
 }
 
 type roomServiceImpl struct {
-	roomRepo   repository.RoomRepository
-	errWarpper dtoError.ServiceErrorWarpper
-	logger     logger.Logger
+	roomRepo    repository.RoomRepository
+	userRepo    repository.AccountRepository
+	blockFilter *blockFilter
+	permission  *permissionEvaluator
+	events      *systemEventWriter
+	errWarpper  dtoError.ServiceErrorWarpper
+	logger      logger.Logger
 }
 
 var room RoomService
 
 func init() {
 	room = &roomServiceImpl{
-		roomRepo:   repository.GetRoomRepository(),
-		errWarpper: dtoError.GetServiceErrorWarpper(),
-		logger:     logger.NewLogger(),
+		roomRepo:    repository.GetRoomRepository(),
+		userRepo:    repository.GetAccountRepository(),
+		blockFilter: newBlockFilter(),
+		permission:  newPermissionEvaluator(),
+		events:      newSystemEventWriter(),
+		errWarpper:  dtoError.GetServiceErrorWarpper(),
+		logger:      logger.NewLogger(),
 	}
 }
 
@@ -41,16 +57,110 @@ func (r *roomServiceImpl) CreateRoom(ctx context.Context, req *dto.CreateRoomReq
 	r.logger.Info(requestId, "start", req, nil)
 	defer func() { r.logger.Info(requestId, "end", req, nil) }()
 
-	roomInfo, ok, err := r.roomRepo.CreateRoom(ctx, req.UserID, req.RoomName, req.Description)
+	if strings.HasPrefix(req.RoomName, repository.DirectRoomNamePrefix) {
+		return nil, r.errWarpper.NewRoomNameUsedError(req.RoomName)
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomInfo, ok, err := r.roomRepo.CreateRoom(txContext, req.UserID, req.RoomName, req.Description)
 	if err != nil {
+		tx.Rollback()
 		r.logger.Error(requestId, "r.roomRepo.CreateRoom", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
 	} else if !ok {
+		tx.Rollback()
 		return nil, r.errWarpper.NewUserHasRegisterdError(req.RoomName)
 	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
 	return &dto.CreateRoomResponse{RoomID: roomInfo.Id}, nil
 }
 
+func (r *roomServiceImpl) OpenDirectRoom(ctx context.Context, req *dto.OpenDirectRoomRequest) (*dto.OpenDirectRoomResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	if req.UserID == req.TargetUserID {
+		return nil, r.errWarpper.NewDirectRoomSelfError(req.UserID)
+	}
+
+	userExist, err := r.userRepo.CheckUserExist(ctx, req.TargetUserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.userRepo.CheckUserExist", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !userExist {
+		return nil, r.errWarpper.NewUserNotExist(req.TargetUserID)
+	}
+
+	blocked, err := r.blockFilter.isBlocked(ctx, req.TargetUserID, req.UserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.blockFilter.isBlocked", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if blocked {
+		return nil, r.errWarpper.NewUserIsBlockedError(req.TargetUserID, req.UserID)
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomInfo, created, err := r.roomRepo.CreateDirectRoom(txContext, req.UserID, req.TargetUserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.CreateDirectRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.OpenDirectRoomResponse{RoomID: roomInfo.Id, Created: created}, nil
+}
+
+// directCounterparts maps each direct room id to the profile of the member who is not userID.
+func (r *roomServiceImpl) directCounterparts(ctx context.Context, userID uint64, rooms []*model.Room) (map[uint64]*dto.UserProfile, error) {
+	counterpartIDs := make(map[uint64]uint64)
+	userIDs := []uint64{}
+	for _, room := range rooms {
+		if room.Type != model.RoomTypeDirect {
+			continue
+		}
+		for _, member := range room.Members {
+			if member.UserID != userID {
+				counterpartIDs[room.Id] = member.UserID
+				userIDs = append(userIDs, member.UserID)
+			}
+		}
+	}
+
+	answer := make(map[uint64]*dto.UserProfile)
+	if len(userIDs) == 0 {
+		return answer, nil
+	}
+
+	users, err := r.userRepo.UsersInfo(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(map[uint64]*dto.UserProfile, len(users))
+	for _, user := range users {
+		profiles[user.Id] = &dto.UserProfile{
+			UserID:   user.Id,
+			Username: user.Username,
+			Name:     user.Name,
+			Email:    user.Email,
+		}
+	}
+	for roomID, counterpartID := range counterpartIDs {
+		answer[roomID] = profiles[counterpartID]
+	}
+	return answer, nil
+}
+
 func (r *roomServiceImpl) GetAvailbleRooms(ctx context.Context, req *dto.GetAvailbleRoomsRequest) (*dto.GetAvailbleRoomsResponse, *dtoError.ServiceError) {
 	requestId := common.GetUUID(ctx)
 	r.logger.Info(requestId, "start", req, nil)
@@ -63,17 +173,38 @@ func (r *roomServiceImpl) GetAvailbleRooms(ctx context.Context, req *dto.GetAvai
 		return nil, r.errWarpper.NewDBServiceError(err)
 	}
 
+	counterparts, err := r.directCounterparts(ctx, req.UserID, roomsInfo)
+	if err != nil {
+		r.logger.Error(requestId, "r.directCounterparts", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
 	answer := make([]dto.ReadRoomInfoResponse, len(roomsInfo))
 	for i, info := range roomsInfo {
 		answer[i].ID = info.Id
 		answer[i].Name = info.Name
 		answer[i].AdminUserID = info.AdminUserID
-		var uid []uint64
-		for _, userid := range info.UserIDs {
-			uid = append(uid, uint64(userid))
-		}
-		answer[i].UserIDs = uid
+		answer[i].UserIDs = roomMemberIDs(info.Members)
 		answer[i].Description = info.Description
+		answer[i].Topic = info.Topic
+		answer[i].AvatarURL = info.AvatarURL
+		answer[i].Capacity = info.Capacity
+		answer[i].Type = info.Type
+		answer[i].JoinPolicy = info.JoinPolicy
+		answer[i].Listed = info.Listed
+		answer[i].Tags = info.Tags
+		answer[i].SlowModeSecond = info.SlowModeSecond
+		if info.ArchivedAt != nil {
+			answer[i].ArchiveTime = common.TimeToUint64(*info.ArchivedAt)
+		}
+		answer[i].Mode = info.Mode
+		answer[i].RetentionDay = info.RetentionDay
+		answer[i].LegalHold = info.LegalHold
+		answer[i].FilterAction = info.FilterAction
+		answer[i].FilterWords = info.FilterWords
+		answer[i].MaxLinks = info.MaxLinks
+		answer[i].Announcement = roomAnnouncement(info)
+		answer[i].Counterpart = counterparts[info.Id]
 	}
 	return &dto.GetAvailbleRoomsResponse{RoomsInfos: answer}, nil
 }
@@ -87,20 +218,47 @@ func (r *roomServiceImpl) ReadRoomInfo(ctx context.Context, req *dto.ReadRoomInf
 	if err != nil {
 		r.logger.Info(requestId, "r.roomRepo.ReadRoomInfo", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if room == nil {
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	var uid []uint64
-	for _, userid := range room.UserIDs {
-		uid = append(uid, uint64(userid))
+	answer := &dto.ReadRoomInfoResponse{
+		ID:             room.Id,
+		Name:           room.Name,
+		AdminUserID:    room.AdminUserID,
+		UserIDs:        roomMemberIDs(room.Members),
+		Description:    room.Description,
+		Topic:          room.Topic,
+		AvatarURL:      room.AvatarURL,
+		Capacity:       room.Capacity,
+		Type:           room.Type,
+		JoinPolicy:     room.JoinPolicy,
+		Listed:         room.Listed,
+		Tags:           room.Tags,
+		SlowModeSecond: room.SlowModeSecond,
 	}
+	if room.ArchivedAt != nil {
+		answer.ArchiveTime = common.TimeToUint64(*room.ArchivedAt)
+	}
+	answer.Mode = room.Mode
+	answer.RetentionDay = room.RetentionDay
+	answer.LegalHold = room.LegalHold
+	answer.FilterAction = room.FilterAction
+	answer.FilterWords = room.FilterWords
+	answer.MaxLinks = room.MaxLinks
+	answer.Announcement = roomAnnouncement(room)
+	return answer, nil
+}
 
-	return &dto.ReadRoomInfoResponse{
-		ID:          req.UserID,
-		Name:        room.Name,
-		AdminUserID: room.AdminUserID,
-		UserIDs:     uid,
-		Description: room.Description,
-	}, nil
+func roomAnnouncement(room *model.Room) *dto.RoomAnnouncement {
+	if room.Announcement == "" || room.AnnouncementTime == nil {
+		return nil
+	}
+	return &dto.RoomAnnouncement{
+		Content:    room.Announcement,
+		UserID:     room.AnnouncementUserID,
+		UpdateTime: common.TimeToUint64(*room.AnnouncementTime),
+	}
 }
 
 func (r *roomServiceImpl) DeleteRoom(ctx context.Context, req *dto.DeleteRoomRequest) (*dto.DeleteRoomResponse, *dtoError.ServiceError) {
@@ -109,35 +267,41 @@ func (r *roomServiceImpl) DeleteRoom(ctx context.Context, req *dto.DeleteRoomReq
 	defer func() { r.logger.Info(requestId, "end", req, nil) }()
 
 	txContext, tx := repository.SetTxContext(ctx)
-	roomExist, err := r.roomRepo.RoomExist(txContext, req.RoomID)
+	// the lock keeps an operator from setting a hold between the check below and the delete
+	room, err := r.roomRepo.LockRoomForHold(txContext, req.RoomID)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.RoomExist", req, err)
+		r.logger.Error(requestId, "r.roomRepo.LockRoomForHold", req, err)
 		serviceErr := r.errWarpper.NewDBServiceError(err)
 		return nil, serviceErr
-	} else if !roomExist {
+	} else if room == nil || room.DeletedAt.Valid {
 		tx.Rollback()
 		serviceErr := r.errWarpper.NewRoomNotExistError(req.RoomID)
 		return nil, serviceErr
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionDeleteRoom)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		serviceErr := r.errWarpper.NewDBServiceError(err)
 		return nil, serviceErr
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		serviceErr := r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		serviceErr := r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionDeleteRoom.String())
 		return nil, serviceErr
+	} else if room.LegalHold {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomUnderHoldError(req.RoomID)
 	}
 
 	ok, err := r.roomRepo.DeleteRoom(txContext, req.RoomID, req.AdminUserID)
 	if err != nil {
+		tx.Rollback()
 		r.logger.Error(requestId, "r.roomRepo.DeleteRoom", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
 	} else if !ok {
+		tx.Rollback()
 		return nil, r.errWarpper.NewDBNoAffectedServiceError()
 	}
 
@@ -149,3 +313,170 @@ func (r *roomServiceImpl) DeleteRoom(ctx context.Context, req *dto.DeleteRoomReq
 	}
 	return &dto.DeleteRoomResponse{}, nil
 }
+
+func (r *roomServiceImpl) SearchRoomDirectory(ctx context.Context, req *dto.SearchRoomDirectoryRequest) (*dto.SearchRoomDirectoryResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	records, err := r.roomRepo.SearchListedRooms(ctx, strings.TrimSpace(req.Keyword), req.Tags, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.SearchListedRooms", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := make([]dto.RoomDirectoryEntry, len(records))
+	for i, record := range records {
+		answer[i].RoomID = record.Id
+		answer[i].Name = record.Name
+		answer[i].Description = record.Description
+		answer[i].JoinPolicy = record.JoinPolicy
+		answer[i].Tags = record.Tags
+		answer[i].MemberCount = record.MemberCount
+	}
+	return &dto.SearchRoomDirectoryResponse{Rooms: answer}, nil
+}
+
+// ArchiveRoom turns a room read only or back, members keep reading the history of an archived room.
+func (r *roomServiceImpl) ArchiveRoom(ctx context.Context, req *dto.ArchiveRoomRequest) (*dto.ArchiveRoomResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomExist, err := r.roomRepo.LockRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.LockRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionArchiveRoom)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionArchiveRoom.String())
+	}
+
+	var ok bool
+	eventType := model.SystemEventRoomArchived
+	if req.Archived {
+		ok, err = r.roomRepo.ArchiveRoom(txContext, req.RoomID)
+	} else {
+		ok, err = r.roomRepo.UnarchiveRoom(txContext, req.RoomID)
+		eventType = model.SystemEventRoomUnarchived
+	}
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.ArchiveRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		// already in the requested state
+		tx.Rollback()
+		return &dto.ArchiveRoomResponse{}, nil
+	}
+
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: eventType, ActorUserID: req.AdminUserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.ArchiveRoomResponse{}, nil
+}
+
+// FetchDeletedRooms lists the rooms the user deleted that are still inside the retention window.
+func (r *roomServiceImpl) FetchDeletedRooms(ctx context.Context, req *dto.FetchDeletedRoomsRequest) (*dto.FetchDeletedRoomsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	retention := repository.DeletedRoomRetention()
+	rooms, err := r.roomRepo.FetchDeletedRooms(ctx, req.AdminUserID, time.Now().Add(-retention))
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.FetchDeletedRooms", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchDeletedRoomsResponse{Rooms: make([]dto.DeletedRoomInfo, len(rooms))}
+	for i, room := range rooms {
+		answer.Rooms[i] = dto.DeletedRoomInfo{
+			RoomID:      room.Id,
+			Name:        room.Name,
+			Description: room.Description,
+			DeleteTime:  common.TimeToUint64(room.DeletedAt.Time),
+			PurgeTime:   common.TimeToUint64(room.DeletedAt.Time.Add(retention)),
+		}
+	}
+	return &answer, nil
+}
+
+// RestoreRoom brings back a deleted room with its members and history, only its owner can do it
+// and only before the purge job removed it.
+func (r *roomServiceImpl) RestoreRoom(ctx context.Context, req *dto.RestoreRoomRequest) (*dto.RestoreRoomResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	room, err := r.roomRepo.ReadDeletedRoom(txContext, req.RoomID, time.Now().Add(-repository.DeletedRoomRetention()))
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.ReadDeletedRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if room == nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNotRestorableError(req.RoomID)
+	} else if room.AdminUserID != req.AdminUserID {
+		tx.Rollback()
+		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+	}
+
+	// the name may have been taken while the room was deleted
+	used, err := r.roomRepo.RoomNameUsed(txContext, room.Name, room.Id)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.RoomNameUsed", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if used {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNameUsedError(room.Name)
+	}
+
+	ok, err := r.roomRepo.RestoreRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.RestoreRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNotRestorableError(req.RoomID)
+	}
+
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventRoomRestored, ActorUserID: req.AdminUserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.RestoreRoomResponse{}, nil
+}
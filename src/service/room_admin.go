@@ -5,8 +5,14 @@ import (
 	"ChatRoomAPI/src/dto"
 	"ChatRoomAPI/src/dtoError"
 	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
 	"ChatRoomAPI/src/repository"
 	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type RoomAdminService interface {
@@ -17,13 +23,34 @@ type RoomAdminService interface {
 	ConfrimApply(ctx context.Context, req *dto.ConfrimApplyRequest) (*dto.ConfrimApplyResponse, *dtoError.ServiceError)
 	FetchApplicationByAdmin(ctx context.Context, req *dto.FetchApplicationByAdminRequest) (*dto.FetchApplicationByAdminResponse, *dtoError.ServiceError)
 	DeleteUser(ctx context.Context, req *dto.DeleteUserRequest) (*dto.DeleteUserResponse, *dtoError.ServiceError)
+	AssignRole(ctx context.Context, req *dto.AssignRoleRequest) (*dto.AssignRoleResponse, *dtoError.ServiceError)
+	CreateInviteLink(ctx context.Context, req *dto.CreateInviteLinkRequest) (*dto.CreateInviteLinkResponse, *dtoError.ServiceError)
+	FetchInviteLinks(ctx context.Context, req *dto.FetchInviteLinksRequest) (*dto.FetchInviteLinksResponse, *dtoError.ServiceError)
+	RevokeInviteLink(ctx context.Context, req *dto.RevokeInviteLinkRequest) (*dto.RevokeInviteLinkResponse, *dtoError.ServiceError)
+	FetchInviteLinkUses(ctx context.Context, req *dto.FetchInviteLinkUsesRequest) (*dto.FetchInviteLinkUsesResponse, *dtoError.ServiceError)
+	SetJoinPolicy(ctx context.Context, req *dto.SetJoinPolicyRequest) (*dto.SetJoinPolicyResponse, *dtoError.ServiceError)
+	BanUser(ctx context.Context, req *dto.BanUserRequest) (*dto.BanUserResponse, *dtoError.ServiceError)
+	UnbanUser(ctx context.Context, req *dto.UnbanUserRequest) (*dto.UnbanUserResponse, *dtoError.ServiceError)
+	FetchBans(ctx context.Context, req *dto.FetchBansRequest) (*dto.FetchBansResponse, *dtoError.ServiceError)
+	FetchFilterLogs(ctx context.Context, req *dto.FetchFilterLogsRequest) (*dto.FetchFilterLogsResponse, *dtoError.ServiceError)
+	MuteUser(ctx context.Context, req *dto.MuteUserRequest) (*dto.MuteUserResponse, *dtoError.ServiceError)
+	SetSlowMode(ctx context.Context, req *dto.SetSlowModeRequest) (*dto.SetSlowModeResponse, *dtoError.ServiceError)
+	UpdateRoomSettings(ctx context.Context, req *dto.UpdateRoomSettingsRequest) (*dto.UpdateRoomSettingsResponse, *dtoError.ServiceError)
+	SetAnnouncement(ctx context.Context, req *dto.SetAnnouncementRequest) (*dto.SetAnnouncementResponse, *dtoError.ServiceError)
 }
 
 type roomAdminServiceImpl struct {
 	roomRepo        repository.RoomRepository
 	applicationRepo repository.ApplicationRepository
 	invitationRepo  repository.InvitationRepository
+	inviteLinkRepo  repository.InviteLinkRepository
+	roomBanRepo     repository.RoomBanRepository
+	filterLogRepo   repository.FilterLogRepository
+	events          *systemEventWriter
 	userRepo        repository.AccountRepository
+	blockFilter     *blockFilter
+	permission      *permissionEvaluator
+	moderation      *moderationActions
 	errWarpper      dtoError.ServiceErrorWarpper
 	logger          logger.Logger
 }
@@ -35,8 +62,15 @@ func init() {
 		roomRepo:        repository.GetRoomRepository(),
 		applicationRepo: repository.GetApplicationRepository(),
 		invitationRepo:  repository.GetInvitationRepository(),
+		inviteLinkRepo:  repository.GetInviteLinkRepository(),
+		roomBanRepo:     repository.GetRoomBanRepository(),
+		filterLogRepo:   repository.GetFilterLogRepository(),
+		events:          newSystemEventWriter(),
 		errWarpper:      dtoError.GetServiceErrorWarpper(),
 		userRepo:        repository.GetAccountRepository(),
+		blockFilter:     newBlockFilter(),
+		permission:      newPermissionEvaluator(),
+		moderation:      newModerationActions(),
 		logger:          logger.NewLogger(),
 	}
 }
@@ -69,14 +103,24 @@ func (r *roomAdminServiceImpl) AdminChange(ctx context.Context, req *dto.AdminCh
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionTransferOwnership)
 	if err != nil {
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		tx.Rollback()
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionTransferOwnership.String())
 	}
 
 	isUser, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
@@ -99,6 +143,13 @@ func (r *roomAdminServiceImpl) AdminChange(ctx context.Context, req *dto.AdminCh
 		return nil, r.errWarpper.NewDBNoAffectedServiceError()
 	}
 
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventOwnerChanged, ActorUserID: req.AdminUserID, TargetUserID: req.UserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
 	err = tx.Commit().Error
 	if err != nil {
 		r.logger.Error(requestId, "tx.Commit", req, err)
@@ -123,14 +174,24 @@ func (r *roomAdminServiceImpl) InviteNewUser(ctx context.Context, req *dto.Invit
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionInvite)
 	if err != nil {
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		tx.Rollback()
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
 	}
 
 	userExist, err := r.userRepo.CheckUserExist(txContext, req.UserID)
@@ -143,6 +204,26 @@ func (r *roomAdminServiceImpl) InviteNewUser(ctx context.Context, req *dto.Invit
 		return nil, r.errWarpper.NewUserNotExist(req.UserID)
 	}
 
+	blocked, err := r.blockFilter.isBlocked(txContext, req.UserID, req.AdminUserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.blockFilter.isBlocked", req, err)
+		tx.Rollback()
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if blocked {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserIsBlockedError(req.UserID, req.AdminUserID)
+	}
+
+	banned, err := r.roomBanRepo.CheckBanned(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomBanRepo.CheckBanned", req, err)
+		tx.Rollback()
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if banned {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserIsBannedError(req.UserID, req.RoomID)
+	}
+
 	isUser, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
 	if err != nil {
 		r.logger.Error(requestId, "r.roomRepo.CheckUserInRoom", req, err)
@@ -184,14 +265,14 @@ func (r *roomAdminServiceImpl) InviteNewUserCancel(ctx context.Context, req *dto
 	defer func() { r.logger.Info(requestId, "end", req, nil) }()
 
 	txContext, tx := repository.SetTxContext(ctx)
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionInvite)
 	if err != nil {
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		tx.Rollback()
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
 	}
 
 	invited, err := r.invitationRepo.CheckInvitationExist(txContext, req.RoomID, req.UserID)
@@ -228,18 +309,18 @@ func (r *roomAdminServiceImpl) FetchInvitationsByAdmin(ctx context.Context, req
 	defer func() { r.logger.Info(requestId, "end", req, nil) }()
 
 	txContext, tx := repository.SetTxContext(ctx)
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminID)
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminID, permissionInvite)
 	if err != nil {
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		tx.Rollback()
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminID, req.RoomID, permissionInvite.String())
 	}
 
 	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
-	records, err := r.invitationRepo.FetchInvitationsByAdmin(txContext, req.AdminID, skip, pageSize)
+	records, err := r.invitationRepo.FetchInvitationsByAdmin(txContext, req.RoomID, skip, pageSize)
 	if err != nil {
 		r.logger.Error(requestId, "r.invitationRepo.FetchInvitationsByAdmin", req, err)
 		tx.Rollback()
@@ -279,14 +360,14 @@ func (r *roomAdminServiceImpl) ConfrimApply(ctx context.Context, req *dto.Confri
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionApprove)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionApprove.String())
 	}
 
 	isUser, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
@@ -310,6 +391,26 @@ func (r *roomAdminServiceImpl) ConfrimApply(ctx context.Context, req *dto.Confri
 	}
 
 	if req.Allowed {
+		archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "roomIsArchived", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if archived {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+		}
+
+		full, err := roomIsFull(txContext, r.roomRepo, req.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "roomIsFull", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if full {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomIsFullError(req.RoomID)
+		}
+
 		_, err = r.roomRepo.AddUser(txContext, req.RoomID, req.UserID)
 		if err != nil {
 			tx.Rollback()
@@ -318,6 +419,22 @@ func (r *roomAdminServiceImpl) ConfrimApply(ctx context.Context, req *dto.Confri
 		}
 	}
 
+	err = r.inviteLinkRepo.SettleInviteLinkUse(txContext, req.RoomID, req.UserID, req.Allowed)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.inviteLinkRepo.SettleInviteLinkUse", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	if req.Allowed {
+		err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventMemberJoined, ActorUserID: req.AdminUserID, TargetUserID: req.UserID})
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.events.write", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+	}
+
 	err = tx.Commit().Error
 	if err != nil {
 		r.logger.Error(requestId, "tx.Commit", req, err)
@@ -343,14 +460,14 @@ func (r *roomAdminServiceImpl) FetchApplicationByAdmin(ctx context.Context, req
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionApprove)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionApprove.String())
 	}
 
 	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
@@ -393,27 +510,30 @@ func (r *roomAdminServiceImpl) DeleteUser(ctx context.Context, req *dto.DeleteUs
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
-	isAdmin, err := r.roomRepo.CheckAdminUserInRoom(txContext, req.RoomID, req.AdminUserID)
+	actor, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionKick)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.CheckAdminUserInRoom", req, err)
+		r.logger.Error(requestId, "r.permission.check", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isAdmin {
+	} else if !allowed {
 		tx.Rollback()
-		return nil, r.errWarpper.NewNotAdminOfRoomError(req.AdminUserID, req.RoomID)
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionKick.String())
 	}
 
-	isUser, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
+	target, err := r.roomRepo.GetMember(txContext, req.RoomID, req.UserID)
 	if err != nil {
 		tx.Rollback()
-		r.logger.Error(requestId, "r.roomRepo.CheckUserInRoom", req, err)
+		r.logger.Error(requestId, "r.roomRepo.GetMember", req, err)
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !isUser {
+	} else if target == nil {
 		tx.Rollback()
 		return nil, r.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	} else if !r.permission.outranks(actor, target) {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionKick.String())
 	}
 
-	ok, err := r.roomRepo.DeleteUser(txContext, req.RoomID, req.AdminUserID, req.UserID)
+	ok, err := r.roomRepo.DeleteUser(txContext, req.RoomID, req.UserID)
 	if err != nil {
 		tx.Rollback()
 		r.logger.Error(requestId, "r.roomRepo.DeleteUser", req, err)
@@ -423,6 +543,13 @@ func (r *roomAdminServiceImpl) DeleteUser(ctx context.Context, req *dto.DeleteUs
 		return nil, r.errWarpper.NewDBNoAffectedServiceError()
 	}
 
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventMemberKicked, ActorUserID: req.AdminUserID, TargetUserID: req.UserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
 	err = tx.Commit().Error
 	if err != nil {
 		r.logger.Error(requestId, "tx.Commit", req, err)
@@ -430,3 +557,664 @@ func (r *roomAdminServiceImpl) DeleteUser(ctx context.Context, req *dto.DeleteUs
 	}
 	return &dto.DeleteUserResponse{}, nil
 }
+
+func (r *roomAdminServiceImpl) AssignRole(ctx context.Context, req *dto.AssignRoleRequest) (*dto.AssignRoleResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	if !assignableRoles[req.Role] {
+		return nil, r.errWarpper.NewInvalidRoomRoleError(req.Role)
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	actor, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionAssignRole)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionAssignRole.String())
+	}
+
+	target, err := r.roomRepo.GetMember(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.GetMember", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if target == nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	} else if !r.permission.outranks(actor, target) {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionAssignRole.String())
+	}
+
+	ok, err := r.roomRepo.UpdateRole(txContext, req.RoomID, req.UserID, req.Role)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.UpdateRole", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		tx.Rollback()
+		return nil, r.errWarpper.NewDBNoAffectedServiceError()
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.AssignRoleResponse{}, nil
+}
+
+// inviteTokenSize is the number of random bytes behind an invite link token.
+const inviteTokenSize = 24
+
+func inviteLinkInfo(link *model.InviteLink) dto.InviteLinkInfo {
+	info := dto.InviteLinkInfo{
+		LinkID:          link.Id,
+		Token:           link.Token,
+		CreatorUserID:   link.CreatorUserID,
+		MaxUses:         link.MaxUses,
+		UseCount:        link.UseCount,
+		RequireApproval: link.RequireApproval,
+	}
+	if link.ExpiresAt != nil {
+		info.ExpireTime = common.TimeToUint64(*link.ExpiresAt)
+	}
+	return info
+}
+
+func (r *roomAdminServiceImpl) CreateInviteLink(ctx context.Context, req *dto.CreateInviteLinkRequest) (*dto.CreateInviteLinkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	roomExist, err := r.roomRepo.RoomExist(ctx, req.RoomID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.RoomExist", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	archived, err := roomIsArchived(ctx, r.roomRepo, req.RoomID)
+	if err != nil {
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionInvite)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
+	}
+
+	token, err := common.RandomToken(inviteTokenSize)
+	if err != nil {
+		r.logger.Error(requestId, "common.RandomToken", req, err)
+		return nil, r.errWarpper.NewInviteTokenFailedError(err)
+	}
+
+	link := model.InviteLink{
+		RoomID:          req.RoomID,
+		Token:           token,
+		CreatorUserID:   req.AdminUserID,
+		MaxUses:         req.MaxUses,
+		RequireApproval: req.RequireApproval,
+	}
+	if req.ExpireSecond > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpireSecond) * time.Second)
+		link.ExpiresAt = &expiresAt
+	}
+
+	err = r.inviteLinkRepo.CreateInviteLink(ctx, &link)
+	if err != nil {
+		r.logger.Error(requestId, "r.inviteLinkRepo.CreateInviteLink", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.CreateInviteLinkResponse{Link: inviteLinkInfo(&link)}, nil
+}
+
+func (r *roomAdminServiceImpl) FetchInviteLinks(ctx context.Context, req *dto.FetchInviteLinksRequest) (*dto.FetchInviteLinksResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionInvite)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
+	}
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	links, err := r.inviteLinkRepo.FetchActiveInviteLinks(ctx, req.RoomID, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.inviteLinkRepo.FetchActiveInviteLinks", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchInviteLinksResponse{RoomID: req.RoomID}
+	answer.Links = make([]dto.InviteLinkInfo, len(links))
+	for i, link := range links {
+		answer.Links[i] = inviteLinkInfo(link)
+	}
+	return &answer, nil
+}
+
+func (r *roomAdminServiceImpl) RevokeInviteLink(ctx context.Context, req *dto.RevokeInviteLinkRequest) (*dto.RevokeInviteLinkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionInvite)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
+	}
+
+	ok, err := r.inviteLinkRepo.RevokeInviteLink(ctx, req.RoomID, req.LinkID)
+	if err != nil {
+		r.logger.Error(requestId, "r.inviteLinkRepo.RevokeInviteLink", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, r.errWarpper.NewInviteLinkNotExistError(req.LinkID, req.RoomID)
+	}
+	return &dto.RevokeInviteLinkResponse{}, nil
+}
+
+func (r *roomAdminServiceImpl) FetchInviteLinkUses(ctx context.Context, req *dto.FetchInviteLinkUsesRequest) (*dto.FetchInviteLinkUsesResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionInvite)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionInvite.String())
+	}
+
+	exist, err := r.inviteLinkRepo.InviteLinkExist(ctx, req.RoomID, req.LinkID)
+	if err != nil {
+		r.logger.Error(requestId, "r.inviteLinkRepo.InviteLinkExist", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !exist {
+		return nil, r.errWarpper.NewInviteLinkNotExistError(req.LinkID, req.RoomID)
+	}
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	records, err := r.inviteLinkRepo.FetchInviteLinkUses(ctx, req.LinkID, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.inviteLinkRepo.FetchInviteLinkUses", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchInviteLinkUsesResponse{LinkID: req.LinkID}
+	answer.Uses = make([]dto.InviteLinkUseInfo, len(records))
+	for i, record := range records {
+		answer.Uses[i].UserID = record.UserId
+		answer.Uses[i].Name = record.Name
+		answer.Uses[i].Email = record.Email
+		answer.Uses[i].Pending = record.Pending
+		answer.Uses[i].JoinTime = common.TimeToUint64(record.CreateTime)
+	}
+	return &answer, nil
+}
+
+var joinPolicies = map[string]bool{
+	model.JoinPolicyOpen:       true,
+	model.JoinPolicyApproval:   true,
+	model.JoinPolicyInviteOnly: true,
+}
+
+// normalizeTags lower-cases and trims tags so the directory search matches them exactly.
+func normalizeTags(rawTags []string) []string {
+	tags := make([]string, 0, len(rawTags))
+	for _, tag := range rawTags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (r *roomAdminServiceImpl) SetJoinPolicy(ctx context.Context, req *dto.SetJoinPolicyRequest) (*dto.SetJoinPolicyResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	if !joinPolicies[req.JoinPolicy] {
+		return nil, r.errWarpper.NewInvalidJoinPolicyError(req.JoinPolicy)
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionEditRoom)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionEditRoom.String())
+	}
+
+	ok, err := r.roomRepo.UpdateJoinPolicy(txContext, req.RoomID, req.JoinPolicy, req.Listed, normalizeTags(req.Tags))
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.UpdateJoinPolicy", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	}
+
+	event := dto.SystemEvent{Type: model.SystemEventSettingsUpdated, ActorUserID: req.AdminUserID, Changed: []string{"join_policy", "listed", "tags"}}
+	err = r.events.write(txContext, req.RoomID, event)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.SetJoinPolicyResponse{}, nil
+}
+
+// BanUser removes the user from the room if present, drops pending invitations and applications and
+// keeps them from coming back until the ban expires. A zero duration bans permanently.
+func (r *roomAdminServiceImpl) BanUser(ctx context.Context, req *dto.BanUserRequest) (*dto.BanUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	serviceErr := r.moderation.ban(txContext, req, false)
+	if serviceErr != nil {
+		tx.Rollback()
+		return nil, serviceErr
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.BanUserResponse{}, nil
+}
+
+func (r *roomAdminServiceImpl) UnbanUser(ctx context.Context, req *dto.UnbanUserRequest) (*dto.UnbanUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionBan)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionBan.String())
+	}
+
+	ok, err := r.roomBanRepo.UnbanUser(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomBanRepo.UnbanUser", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, r.errWarpper.NewUserNotBannedError(req.UserID, req.RoomID)
+	}
+	return &dto.UnbanUserResponse{}, nil
+}
+
+func (r *roomAdminServiceImpl) FetchBans(ctx context.Context, req *dto.FetchBansRequest) (*dto.FetchBansResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionBan)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionBan.String())
+	}
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	bans, err := r.roomBanRepo.FetchBans(ctx, req.RoomID, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomBanRepo.FetchBans", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchBansResponse{RoomID: req.RoomID}
+	answer.Bans = make([]dto.BanInfo, len(bans))
+	for i, ban := range bans {
+		answer.Bans[i].UserID = ban.UserID
+		answer.Bans[i].BannedByUserID = ban.BannedByUserID
+		answer.Bans[i].Reason = ban.Reason
+		answer.Bans[i].CreateTime = common.TimeToUint64(ban.CreatedAt)
+		if ban.ExpiresAt != nil {
+			answer.Bans[i].ExpireTime = common.TimeToUint64(*ban.ExpiresAt)
+		}
+	}
+	return &answer, nil
+}
+
+// FetchFilterLogs lists the messages the content filters masked or rejected in a room.
+func (r *roomAdminServiceImpl) FetchFilterLogs(ctx context.Context, req *dto.FetchFilterLogsRequest) (*dto.FetchFilterLogsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionDeleteMessage)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionDeleteMessage.String())
+	}
+
+	skip, pageSize := GetSkip(int(req.Page), int(req.PageSize))
+	logs, err := r.filterLogRepo.FetchFilterLogs(ctx, req.RoomID, skip, pageSize)
+	if err != nil {
+		r.logger.Error(requestId, "r.filterLogRepo.FetchFilterLogs", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchFilterLogsResponse{RoomID: req.RoomID}
+	answer.Logs = make([]dto.FilterLogInfo, len(logs))
+	for i, log := range logs {
+		answer.Logs[i].UserID = log.UserID
+		answer.Logs[i].Filter = log.Filter
+		answer.Logs[i].Action = log.Action
+		answer.Logs[i].Reason = log.Reason
+		answer.Logs[i].Content = log.Content
+		answer.Logs[i].CreateTime = common.TimeToUint64(log.CreatedAt)
+	}
+	return &answer, nil
+}
+
+// MuteUser silences a member for DurationSecond, a zero duration lifts an existing mute.
+func (r *roomAdminServiceImpl) MuteUser(ctx context.Context, req *dto.MuteUserRequest) (*dto.MuteUserResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	return r.moderation.mute(ctx, req, false)
+}
+
+// SetSlowMode limits every member to one message per Second, zero turns slow mode off.
+func (r *roomAdminServiceImpl) SetSlowMode(ctx context.Context, req *dto.SetSlowModeRequest) (*dto.SetSlowModeResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionEditRoom)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionEditRoom.String())
+	}
+
+	ok, err := r.roomRepo.UpdateSlowMode(txContext, req.RoomID, req.Second)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.UpdateSlowMode", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	}
+
+	event := dto.SystemEvent{Type: model.SystemEventSettingsUpdated, ActorUserID: req.AdminUserID, Changed: []string{"slow_mode"}}
+	err = r.events.write(txContext, req.RoomID, event)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.SetSlowModeResponse{}, nil
+}
+
+func (r *roomAdminServiceImpl) UpdateRoomSettings(ctx context.Context, req *dto.UpdateRoomSettingsRequest) (*dto.UpdateRoomSettingsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomExist, err := r.roomRepo.LockRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.LockRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	_, allowed, err := r.permission.check(txContext, req.RoomID, req.AdminUserID, permissionEditRoom)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		tx.Rollback()
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionEditRoom.String())
+	}
+
+	room, err := r.roomRepo.ReadRoomInfo(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.ReadRoomInfo", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if room.Type == model.RoomTypeDirect {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	} else if room.ArchivedAt != nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	settings := map[string]interface{}{}
+	changed := []string{}
+	if req.Name != nil && strings.TrimSpace(*req.Name) != room.Name {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || strings.HasPrefix(name, repository.DirectRoomNamePrefix) {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomNameUsedError(name)
+		}
+
+		used, err := r.roomRepo.RoomNameUsed(txContext, name, req.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.roomRepo.RoomNameUsed", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if used {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomNameUsedError(name)
+		}
+		settings["name"] = name
+		changed = append(changed, "room_name")
+	}
+	if req.Description != nil && *req.Description != room.Description {
+		settings["description"] = *req.Description
+		changed = append(changed, "description")
+	}
+	if req.Topic != nil && *req.Topic != room.Topic {
+		settings["topic"] = *req.Topic
+		changed = append(changed, "topic")
+	}
+	if req.AvatarURL != nil && *req.AvatarURL != room.AvatarURL {
+		settings["avatar_url"] = *req.AvatarURL
+		changed = append(changed, "avatar_url")
+	}
+	if req.Tags != nil {
+		if tags := normalizeTags(*req.Tags); !slices.Equal(tags, room.Tags) {
+			settings["tags"] = pq.StringArray(tags)
+			changed = append(changed, "tags")
+		}
+	}
+	if req.Capacity != nil && *req.Capacity != room.Capacity {
+		settings["capacity"] = *req.Capacity
+		changed = append(changed, "capacity")
+	}
+	if req.Mode != nil && *req.Mode != room.Mode {
+		settings["mode"] = *req.Mode
+		changed = append(changed, "mode")
+	}
+	if req.RetentionDay != nil && *req.RetentionDay != room.RetentionDay {
+		settings["retention_day"] = *req.RetentionDay
+		changed = append(changed, "retention_day")
+	}
+	if req.FilterAction != nil && *req.FilterAction != room.FilterAction {
+		settings["filter_action"] = *req.FilterAction
+		changed = append(changed, "filter_action")
+	}
+	if req.FilterWords != nil {
+		if words := normalizeTags(*req.FilterWords); !slices.Equal(words, room.FilterWords) {
+			settings["filter_words"] = pq.StringArray(words)
+			changed = append(changed, "filter_words")
+		}
+	}
+	if req.MaxLinks != nil && *req.MaxLinks != room.MaxLinks {
+		settings["max_links"] = *req.MaxLinks
+		changed = append(changed, "max_links")
+	}
+
+	if len(changed) == 0 {
+		tx.Rollback()
+		return &dto.UpdateRoomSettingsResponse{Changed: changed}, nil
+	}
+
+	_, err = r.roomRepo.UpdateSettings(txContext, req.RoomID, settings)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.UpdateSettings", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	event := dto.SystemEvent{Type: model.SystemEventSettingsUpdated, ActorUserID: req.AdminUserID, Changed: changed}
+	err = r.events.write(txContext, req.RoomID, event)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.UpdateRoomSettingsResponse{Changed: changed}, nil
+}
+
+// SetAnnouncement replaces the banner shown by ReadRoomInfo, an empty content removes it.
+func (r *roomAdminServiceImpl) SetAnnouncement(ctx context.Context, req *dto.SetAnnouncementRequest) (*dto.SetAnnouncementResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionEditRoom)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionEditRoom.String())
+	}
+
+	room, err := r.roomRepo.ReadRoomInfo(ctx, req.RoomID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.ReadRoomInfo", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if room == nil {
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	} else if room.Type == model.RoomTypeDirect {
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	} else if room.ArchivedAt != nil {
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	content := strings.TrimSpace(req.Content)
+	settings := map[string]interface{}{"announcement": content, "announcement_user_id": req.AdminUserID, "announcement_time": time.Now()}
+	if content == "" {
+		settings = map[string]interface{}{"announcement": "", "announcement_user_id": 0, "announcement_time": nil}
+	}
+
+	txContext, tx := repository.SetTxContext(ctx)
+	_, err = r.roomRepo.UpdateSettings(txContext, req.RoomID, settings)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.UpdateSettings", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventAnnouncementSet, ActorUserID: req.AdminUserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.SetAnnouncementResponse{}, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/export"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"fmt"
+)
+
+// exportDownloadPath is where the controller serves finished exports, relative to the api root.
+const exportDownloadPath = "/export/download?token="
+
+type RoomExportService interface {
+	CreateRoomExport(ctx context.Context, req *dto.CreateRoomExportRequest) (*dto.CreateRoomExportResponse, *dtoError.ServiceError)
+	FetchRoomExport(ctx context.Context, req *dto.FetchRoomExportRequest) (*dto.FetchRoomExportResponse, *dtoError.ServiceError)
+	DownloadRoomExport(ctx context.Context, req *dto.DownloadRoomExportRequest) (*dto.DownloadRoomExportResponse, *dtoError.ServiceError)
+}
+
+type roomExportServiceImpl struct {
+	exportRepo repository.RoomExportRepository
+	roomRepo   repository.RoomRepository
+	permission *permissionEvaluator
+	errWarpper dtoError.ServiceErrorWarpper
+	logger     logger.Logger
+}
+
+var roomExport RoomExportService
+
+func init() {
+	roomExport = &roomExportServiceImpl{
+		exportRepo: repository.GetRoomExportRepository(),
+		roomRepo:   repository.GetRoomRepository(),
+		permission: newPermissionEvaluator(),
+		errWarpper: dtoError.GetServiceErrorWarpper(),
+		logger:     logger.NewLogger(),
+	}
+}
+
+func GetRoomExportService() RoomExportService {
+	return roomExport
+}
+
+// CreateRoomExport queues a transcript export, the worker builds it in the background.
+func (r *roomExportServiceImpl) CreateRoomExport(ctx context.Context, req *dto.CreateRoomExportRequest) (*dto.CreateRoomExportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	_, allowed, err := r.permission.check(ctx, req.RoomID, req.AdminUserID, permissionExport)
+	if err != nil {
+		r.logger.Error(requestId, "r.permission.check", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !allowed {
+		return nil, r.errWarpper.NewPermissionDeniedError(req.AdminUserID, req.RoomID, permissionExport.String())
+	}
+
+	token, err := common.RandomToken(32)
+	if err != nil {
+		r.logger.Error(requestId, "common.RandomToken", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	job := model.RoomExport{
+		RoomID:            req.RoomID,
+		RequestedByUserID: req.AdminUserID,
+		Format:            req.Format,
+		Status:            model.ExportStatusPending,
+		Token:             token,
+	}
+	err = r.exportRepo.CreateExport(ctx, &job)
+	if err != nil {
+		r.logger.Error(requestId, "r.exportRepo.CreateExport", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.CreateRoomExportResponse{ExportID: job.Id}, nil
+}
+
+// FetchRoomExport reports the progress of an export to its requester and the other room admins.
+func (r *roomExportServiceImpl) FetchRoomExport(ctx context.Context, req *dto.FetchRoomExportRequest) (*dto.FetchRoomExportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	job, err := r.exportRepo.GetExport(ctx, req.ExportID)
+	if err != nil {
+		r.logger.Error(requestId, "r.exportRepo.GetExport", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if job == nil {
+		return nil, r.errWarpper.NewExportNotExistError(req.ExportID)
+	}
+
+	if job.RequestedByUserID != req.AdminUserID {
+		_, allowed, err := r.permission.check(ctx, job.RoomID, req.AdminUserID, permissionExport)
+		if err != nil {
+			r.logger.Error(requestId, "r.permission.check", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if !allowed {
+			// do not tell other users which exports exist
+			return nil, r.errWarpper.NewExportNotExistError(req.ExportID)
+		}
+	}
+
+	answer := dto.FetchRoomExportResponse{
+		ExportID:     job.Id,
+		RoomID:       job.RoomID,
+		Format:       job.Format,
+		Status:       job.Status,
+		Error:        job.Error,
+		MessageCount: job.MessageCount,
+		CreateTime:   common.TimeToUint64(job.CreatedAt),
+	}
+	if job.FinishedAt != nil {
+		answer.FinishTime = common.TimeToUint64(*job.FinishedAt)
+	}
+	if job.Status == model.ExportStatusDone && job.ExpiresAt != nil {
+		answer.ExpireTime = common.TimeToUint64(*job.ExpiresAt)
+		answer.DownloadPath = exportDownloadPath + job.Token
+	}
+	return &answer, nil
+}
+
+// DownloadRoomExport needs nothing but the token, so the link can be handed to somebody else.
+func (r *roomExportServiceImpl) DownloadRoomExport(ctx context.Context, req *dto.DownloadRoomExportRequest) (*dto.DownloadRoomExportResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", nil, nil)
+	defer func() { r.logger.Info(requestId, "end", nil, nil) }()
+
+	job, err := r.exportRepo.GetExportByToken(ctx, req.Token)
+	if err != nil {
+		r.logger.Error(requestId, "r.exportRepo.GetExportByToken", nil, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if job == nil {
+		return nil, r.errWarpper.NewExportLinkInvalidError()
+	}
+
+	return &dto.DownloadRoomExportResponse{
+		Filename:    fmt.Sprintf("room-%d-%s.%s", job.RoomID, job.CreatedAt.Format("20060102-150405"), job.Format),
+		ContentType: export.ContentType(job.Format),
+		Content:     job.Content,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"ChatRoomAPI/src/dto"
 	"ChatRoomAPI/src/dtoError"
 	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
 	"ChatRoomAPI/src/repository"
 	"context"
 )
@@ -15,12 +16,18 @@ type RoomUserService interface {
 	RoomJoinApply(ctx context.Context, req *dto.RoomJoinApplyRequest) (*dto.RoomJoinApplyResponse, *dtoError.ServiceError)
 	RoomJoinApplyCancel(ctx context.Context, req *dto.RoomJoinApplyCancelRequest) (*dto.RoomJoinApplyCancelResponse, *dtoError.ServiceError)
 	FetchApplicationByUser(ctx context.Context, req *dto.FetchApplicationByUserRequest) (*dto.FetchApplicationByUserResponse, *dtoError.ServiceError)
+	UpdateNickname(ctx context.Context, req *dto.UpdateNicknameRequest) (*dto.UpdateNicknameResponse, *dtoError.ServiceError)
+	LeaveRoom(ctx context.Context, req *dto.LeaveRoomRequest) (*dto.LeaveRoomResponse, *dtoError.ServiceError)
+	JoinByInviteLink(ctx context.Context, req *dto.JoinByInviteLinkRequest) (*dto.JoinByInviteLinkResponse, *dtoError.ServiceError)
 }
 
 type roomUserServiceImpl struct {
 	roomRepo        repository.RoomRepository
 	applicationRepo repository.ApplicationRepository
 	invitationRepo  repository.InvitationRepository
+	inviteLinkRepo  repository.InviteLinkRepository
+	roomBanRepo     repository.RoomBanRepository
+	events          *systemEventWriter
 	errWarpper      dtoError.ServiceErrorWarpper
 	logger          logger.Logger
 }
@@ -32,6 +39,9 @@ func init() {
 		roomRepo:        repository.GetRoomRepository(),
 		applicationRepo: repository.GetApplicationRepository(),
 		invitationRepo:  repository.GetInvitationRepository(),
+		inviteLinkRepo:  repository.GetInviteLinkRepository(),
+		roomBanRepo:     repository.GetRoomBanRepository(),
+		events:          newSystemEventWriter(),
 		errWarpper:      dtoError.GetServiceErrorWarpper(),
 		logger:          logger.NewLogger(),
 	}
@@ -57,6 +67,26 @@ func (r *roomUserServiceImpl) ConfrimInvite(ctx context.Context, req *dto.Confri
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
 	}
 
+	archived, err := roomIsArchived(txContext, r.roomRepo, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	banned, err := r.roomBanRepo.CheckBanned(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomBanRepo.CheckBanned", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if banned {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserIsBannedError(req.UserID, req.RoomID)
+	}
+
 	InRoom, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
 	if err != nil {
 		tx.Rollback()
@@ -78,6 +108,16 @@ func (r *roomUserServiceImpl) ConfrimInvite(ctx context.Context, req *dto.Confri
 	}
 
 	if req.Allowed {
+		full, err := roomIsFull(txContext, r.roomRepo, req.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "roomIsFull", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if full {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomIsFullError(req.RoomID)
+		}
+
 		_, err = r.roomRepo.AddUser(txContext, req.RoomID, req.UserID)
 		if err != nil {
 			r.logger.Error(requestId, "r.roomRepo.AddUser", req, err)
@@ -86,6 +126,15 @@ func (r *roomUserServiceImpl) ConfrimInvite(ctx context.Context, req *dto.Confri
 		}
 	}
 
+	if req.Allowed {
+		err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventInvitationAccepted, ActorUserID: req.UserID})
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.events.write", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+	}
+
 	err = tx.Commit().Error
 	if err != nil {
 		r.logger.Error(requestId, "tx.Commit", req, err)
@@ -126,14 +175,33 @@ func (r *roomUserServiceImpl) RoomJoinApply(ctx context.Context, req *dto.RoomJo
 	defer func() { r.logger.Info(requestId, "end", req, nil) }()
 
 	txContext, tx := repository.SetTxContext(ctx)
-	roomExist, err := r.roomRepo.RoomExist(txContext, req.RoomID)
+	roomInfo, err := r.roomRepo.ReadRoomInfo(txContext, req.RoomID)
 	if err != nil {
-		r.logger.Error(requestId, "r.roomRepo.RoomExist", req, err)
+		r.logger.Error(requestId, "r.roomRepo.ReadRoomInfo", req, err)
 		tx.Rollback()
 		return nil, r.errWarpper.NewDBServiceError(err)
-	} else if !roomExist {
+	} else if roomInfo == nil {
 		tx.Rollback()
 		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	} else if roomInfo.Type == model.RoomTypeDirect {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	} else if roomInfo.ArchivedAt != nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	} else if roomInfo.JoinPolicy == model.JoinPolicyInviteOnly {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomInviteOnlyError(req.RoomID)
+	}
+
+	banned, err := r.roomBanRepo.CheckBanned(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomBanRepo.CheckBanned", req, err)
+		tx.Rollback()
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if banned {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserIsBannedError(req.UserID, req.RoomID)
 	}
 
 	isUser, err := r.roomRepo.CheckUserInRoom(txContext, req.RoomID, req.UserID)
@@ -146,6 +214,43 @@ func (r *roomUserServiceImpl) RoomJoinApply(ctx context.Context, req *dto.RoomJo
 		return nil, r.errWarpper.NewUserAlreadyInRoomError(req.UserID, req.RoomID)
 	}
 
+	// open rooms accept the application right away instead of queueing it for an admin
+	if roomInfo.JoinPolicy == model.JoinPolicyOpen {
+		full, err := roomIsFull(txContext, r.roomRepo, req.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "roomIsFull", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if full {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomIsFullError(req.RoomID)
+		}
+
+		ok, err := r.roomRepo.AddUser(txContext, req.RoomID, req.UserID)
+		if err != nil {
+			r.logger.Error(requestId, "r.roomRepo.AddUser", req, err)
+			tx.Rollback()
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if !ok {
+			tx.Rollback()
+			return nil, r.errWarpper.NewDBNoAffectedServiceError()
+		}
+
+		err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventMemberJoined, ActorUserID: req.UserID, TargetUserID: req.UserID})
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.events.write", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+
+		err = tx.Commit().Error
+		if err != nil {
+			r.logger.Error(requestId, "tx.Commit", req, err)
+			return nil, r.errWarpper.NewDBCommitServiceError(err)
+		}
+		return &dto.RoomJoinApplyResponse{Joined: true}, nil
+	}
+
 	exist, err := r.applicationRepo.CheckApplicationExist(txContext, req.RoomID, req.UserID)
 	if err != nil {
 		r.logger.Error(requestId, "r.applicationRepo.CheckApplicationExist", req, err)
@@ -231,3 +336,258 @@ func (r *roomUserServiceImpl) FetchApplicationByUser(ctx context.Context, req *d
 	}
 	return &answer, nil
 }
+
+func (r *roomUserServiceImpl) UpdateNickname(ctx context.Context, req *dto.UpdateNicknameRequest) (*dto.UpdateNicknameResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	archived, err := roomIsArchived(ctx, r.roomRepo, req.RoomID)
+	if err != nil {
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		return nil, r.errWarpper.NewRoomIsArchivedError(req.RoomID)
+	}
+
+	ok, err := r.roomRepo.UpdateNickname(ctx, req.RoomID, req.UserID, req.Nickname)
+	if err != nil {
+		r.logger.Error(requestId, "r.roomRepo.UpdateNickname", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, r.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+	return &dto.UpdateNicknameResponse{}, nil
+}
+
+func (r *roomUserServiceImpl) LeaveRoom(ctx context.Context, req *dto.LeaveRoomRequest) (*dto.LeaveRoomResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	roomExist, err := r.roomRepo.LockRoom(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.LockRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !roomExist {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomNotExistError(req.RoomID)
+	}
+
+	roomInfo, err := r.roomRepo.ReadRoomInfo(txContext, req.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.ReadRoomInfo", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if roomInfo.Type == model.RoomTypeDirect {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsDirectError(req.RoomID)
+	}
+
+	var leaving *model.RoomMember
+	for _, member := range roomInfo.Members {
+		if member.UserID == req.UserID {
+			leaving = member
+		}
+	}
+	if leaving == nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	answer := dto.LeaveRoomResponse{}
+	if leaving.Role == model.RoomRoleOwner {
+		// members are ordered by joined_at, so the first other member is the longest-standing one
+		var successor *model.RoomMember
+		for _, member := range roomInfo.Members {
+			if member.UserID == req.UserID {
+				continue
+			}
+			if req.SuccessorUserID == 0 || member.UserID == req.SuccessorUserID {
+				successor = member
+				break
+			}
+		}
+
+		if successor == nil && req.SuccessorUserID != 0 {
+			tx.Rollback()
+			return nil, r.errWarpper.NewUserNotInRoomError(req.SuccessorUserID, req.RoomID)
+		} else if successor == nil {
+			_, err = r.roomRepo.ArchiveRoom(txContext, req.RoomID)
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error(requestId, "r.roomRepo.ArchiveRoom", req, err)
+				return nil, r.errWarpper.NewDBServiceError(err)
+			}
+
+			// nobody is left to own the room
+			_, err = r.roomRepo.UpdateSettings(txContext, req.RoomID, map[string]interface{}{"admin_user_id": 0})
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error(requestId, "r.roomRepo.UpdateSettings", req, err)
+				return nil, r.errWarpper.NewDBServiceError(err)
+			}
+			answer.Archived = true
+
+			err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventRoomArchived, ActorUserID: req.UserID})
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error(requestId, "r.events.write", req, err)
+				return nil, r.errWarpper.NewDBServiceError(err)
+			}
+		} else {
+			ok, err := r.roomRepo.AdminChange(txContext, req.RoomID, req.UserID, successor.UserID)
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error(requestId, "r.roomRepo.AdminChange", req, err)
+				return nil, r.errWarpper.NewDBServiceError(err)
+			} else if !ok {
+				tx.Rollback()
+				return nil, r.errWarpper.NewDBNoAffectedServiceError()
+			}
+			answer.NewOwnerUserID = successor.UserID
+
+			err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventOwnerChanged, ActorUserID: req.UserID, TargetUserID: successor.UserID})
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error(requestId, "r.events.write", req, err)
+				return nil, r.errWarpper.NewDBServiceError(err)
+			}
+		}
+	}
+
+	ok, err := r.roomRepo.DeleteUser(txContext, req.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.DeleteUser", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		tx.Rollback()
+		return nil, r.errWarpper.NewDBNoAffectedServiceError()
+	}
+
+	err = r.events.write(txContext, req.RoomID, dto.SystemEvent{Type: model.SystemEventMemberLeft, ActorUserID: req.UserID})
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.events.write", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &answer, nil
+}
+
+func (r *roomUserServiceImpl) JoinByInviteLink(ctx context.Context, req *dto.JoinByInviteLinkRequest) (*dto.JoinByInviteLinkResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	r.logger.Info(requestId, "start", req, nil)
+	defer func() { r.logger.Info(requestId, "end", req, nil) }()
+
+	txContext, tx := repository.SetTxContext(ctx)
+	link, err := r.inviteLinkRepo.ConsumeInviteLink(txContext, req.Token)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.inviteLinkRepo.ConsumeInviteLink", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if link == nil {
+		tx.Rollback()
+		return nil, r.errWarpper.NewInviteLinkInvalidError()
+	}
+
+	banned, err := r.roomBanRepo.CheckBanned(txContext, link.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomBanRepo.CheckBanned", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if banned {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserIsBannedError(req.UserID, link.RoomID)
+	}
+
+	archived, err := roomIsArchived(txContext, r.roomRepo, link.RoomID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "roomIsArchived", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if archived {
+		tx.Rollback()
+		return nil, r.errWarpper.NewRoomIsArchivedError(link.RoomID)
+	}
+
+	isUser, err := r.roomRepo.CheckUserInRoom(txContext, link.RoomID, req.UserID)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.roomRepo.CheckUserInRoom", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	} else if isUser {
+		tx.Rollback()
+		return nil, r.errWarpper.NewUserAlreadyInRoomError(req.UserID, link.RoomID)
+	}
+
+	if link.RequireApproval {
+		exist, err := r.applicationRepo.CheckApplicationExist(txContext, link.RoomID, req.UserID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.applicationRepo.CheckApplicationExist", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if exist {
+			tx.Rollback()
+			return nil, r.errWarpper.NewUserApplyError(req.UserID, link.RoomID)
+		}
+
+		_, err = r.applicationRepo.RoomJoinApply(txContext, link.RoomID, req.UserID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.applicationRepo.RoomJoinApply", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+	} else {
+		full, err := roomIsFull(txContext, r.roomRepo, link.RoomID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "roomIsFull", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if full {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomIsFullError(link.RoomID)
+		}
+
+		ok, err := r.roomRepo.AddUser(txContext, link.RoomID, req.UserID)
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.roomRepo.AddUser", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		} else if !ok {
+			tx.Rollback()
+			return nil, r.errWarpper.NewRoomNotExistError(link.RoomID)
+		}
+	}
+
+	err = r.inviteLinkRepo.RecordInviteLinkUse(txContext, link.Id, req.UserID, link.RequireApproval)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error(requestId, "r.inviteLinkRepo.RecordInviteLinkUse", req, err)
+		return nil, r.errWarpper.NewDBServiceError(err)
+	}
+
+	if !link.RequireApproval {
+		err = r.events.write(txContext, link.RoomID, dto.SystemEvent{Type: model.SystemEventMemberJoined, ActorUserID: req.UserID, TargetUserID: req.UserID})
+		if err != nil {
+			tx.Rollback()
+			r.logger.Error(requestId, "r.events.write", req, err)
+			return nil, r.errWarpper.NewDBServiceError(err)
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		r.logger.Error(requestId, "tx.Commit", req, err)
+		return nil, r.errWarpper.NewDBCommitServiceError(err)
+	}
+	return &dto.JoinByInviteLinkResponse{RoomID: link.RoomID, Pending: link.RequireApproval}, nil
+}
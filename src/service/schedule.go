@@ -0,0 +1,320 @@
+package service
+
+import (
+	"ChatRoomAPI/src/common"
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/dtoError"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+// maxScheduleAhead is how far in the future a job can be scheduled.
+const maxScheduleAhead = 365 * 24 * time.Hour
+
+// firedRemindersPageSize caps the reminders returned by one FetchFiredReminders call.
+const firedRemindersPageSize = 100
+
+type ScheduleService interface {
+	ScheduleMessage(ctx context.Context, req *dto.ScheduleMessageRequest) (*dto.CreateScheduledJobResponse, *dtoError.ServiceError)
+	SetReminder(ctx context.Context, req *dto.SetReminderRequest) (*dto.CreateScheduledJobResponse, *dtoError.ServiceError)
+	FetchScheduledJobs(ctx context.Context, req *dto.FetchScheduledJobsRequest) (*dto.FetchScheduledJobsResponse, *dtoError.ServiceError)
+	UpdateScheduledJob(ctx context.Context, req *dto.UpdateScheduledJobRequest) (*dto.UpdateScheduledJobResponse, *dtoError.ServiceError)
+	CancelScheduledJob(ctx context.Context, req *dto.CancelScheduledJobRequest) (*dto.CancelScheduledJobResponse, *dtoError.ServiceError)
+	FetchFiredReminders(ctx context.Context, req *dto.FetchFiredRemindersRequest) (*dto.FetchFiredRemindersResponse, *dtoError.ServiceError)
+	AckReminders(ctx context.Context, req *dto.AckRemindersRequest) (*dto.AckRemindersResponse, *dtoError.ServiceError)
+}
+
+type scheduleServiceImpl struct {
+	scheduleRepo repository.ScheduledJobRepository
+	roomRepo     repository.RoomRepository
+	messageRepo  repository.MessageRepository
+	stickers     *stickerFilter
+	errWarpper   dtoError.ServiceErrorWarpper
+	logger       logger.Logger
+}
+
+var scheduleService ScheduleService
+
+func init() {
+	scheduleService = &scheduleServiceImpl{
+		scheduleRepo: repository.GetScheduledJobRepository(),
+		roomRepo:     repository.GetRoomRepository(),
+		messageRepo:  repository.GetMessageRepository(),
+		stickers:     newStickerFilter(),
+		errWarpper:   dtoError.GetServiceErrorWarpper(),
+		logger:       logger.NewLogger(),
+	}
+}
+
+func GetScheduleService() ScheduleService {
+	return scheduleService
+}
+
+// ScheduleMessage checks the membership and strips stickers the user does not own up front, the
+// message goes through AddMessage when it is due and is checked again there.
+func (s *scheduleServiceImpl) ScheduleMessage(ctx context.Context, req *dto.ScheduleMessageRequest) (*dto.CreateScheduledJobResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	runAt := common.Uint64ToTime(req.RunTime)
+	if !validScheduleTime(runAt) {
+		return nil, s.errWarpper.NewInvalidScheduleTimeError(runAt)
+	}
+
+	inRoom, err := s.roomRepo.CheckUserInRoom(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		s.logger.Error(requestId, "s.roomRepo.CheckUserInRoom", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		return nil, s.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	content, err := s.stickers.strip(ctx, req.Content, req.UserID)
+	if err != nil {
+		s.logger.Error(requestId, "s.stickers.strip", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+
+	job := model.ScheduledJob{
+		Kind:    model.ScheduledJobKindMessage,
+		UserID:  req.UserID,
+		RoomID:  req.RoomID,
+		Content: content,
+		RunAt:   runAt,
+		Status:  model.ScheduledJobStatusPending,
+	}
+	err = s.scheduleRepo.CreateScheduledJob(ctx, &job)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.CreateScheduledJob", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.CreateScheduledJobResponse{JobID: job.Id}, nil
+}
+
+func (s *scheduleServiceImpl) SetReminder(ctx context.Context, req *dto.SetReminderRequest) (*dto.CreateScheduledJobResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	runAt := common.Uint64ToTime(req.RunTime)
+	if !validScheduleTime(runAt) {
+		return nil, s.errWarpper.NewInvalidScheduleTimeError(runAt)
+	}
+
+	inRoom, err := s.roomRepo.CheckUserInRoom(ctx, req.RoomID, req.UserID)
+	if err != nil {
+		s.logger.Error(requestId, "s.roomRepo.CheckUserInRoom", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if !inRoom {
+		return nil, s.errWarpper.NewUserNotInRoomError(req.UserID, req.RoomID)
+	}
+
+	message, err := s.messageRepo.GetMessage(ctx, req.RoomID, req.MessageID)
+	if err != nil {
+		s.logger.Error(requestId, "s.messageRepo.GetMessage", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if message == nil {
+		return nil, s.errWarpper.NewMessageNotExistError(req.MessageID, req.RoomID)
+	}
+
+	job := model.ScheduledJob{
+		Kind:      model.ScheduledJobKindReminder,
+		UserID:    req.UserID,
+		RoomID:    req.RoomID,
+		MessageID: req.MessageID,
+		Content:   req.Note,
+		RunAt:     runAt,
+		Status:    model.ScheduledJobStatusPending,
+	}
+	err = s.scheduleRepo.CreateScheduledJob(ctx, &job)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.CreateScheduledJob", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.CreateScheduledJobResponse{JobID: job.Id}, nil
+}
+
+func (s *scheduleServiceImpl) FetchScheduledJobs(ctx context.Context, req *dto.FetchScheduledJobsRequest) (*dto.FetchScheduledJobsResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	status := req.Status
+	if status == "" {
+		status = model.ScheduledJobStatusPending
+	}
+	jobs, err := s.scheduleRepo.FetchScheduledJobs(ctx, req.UserID, req.Kind, status)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.FetchScheduledJobs", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchScheduledJobsResponse{Jobs: make([]dto.ScheduledJob, 0, len(jobs))}
+	for _, job := range jobs {
+		item := dto.ScheduledJob{
+			JobID:      job.Id,
+			Kind:       job.Kind,
+			RoomID:     job.RoomID,
+			MessageID:  job.MessageID,
+			Content:    job.Content,
+			Status:     job.Status,
+			Error:      job.Error,
+			RunTime:    common.TimeToUint64(job.RunAt),
+			CreateTime: common.TimeToUint64(job.CreatedAt),
+		}
+		if job.FinishedAt != nil {
+			item.FinishTime = common.TimeToUint64(*job.FinishedAt)
+		}
+		answer.Jobs = append(answer.Jobs, item)
+	}
+	return &answer, nil
+}
+
+func (s *scheduleServiceImpl) UpdateScheduledJob(ctx context.Context, req *dto.UpdateScheduledJobRequest) (*dto.UpdateScheduledJobResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	settings := map[string]interface{}{}
+	if req.RunTime != nil {
+		runAt := common.Uint64ToTime(*req.RunTime)
+		if !validScheduleTime(runAt) {
+			return nil, s.errWarpper.NewInvalidScheduleTimeError(runAt)
+		}
+		settings["run_time"] = runAt
+	}
+	if req.Content != nil {
+		settings["content"] = *req.Content
+	}
+
+	job, serviceErr := s.ownPendingJob(ctx, requestId, req.UserID, req.JobID)
+	if serviceErr != nil {
+		return nil, serviceErr
+	} else if job.Kind == model.ScheduledJobKindMessage && req.Content != nil && *req.Content == "" {
+		return nil, s.errWarpper.NewParseFormatFailedServiceError(nil, "content of a scheduled message can not be empty")
+	} else if len(settings) == 0 {
+		return &dto.UpdateScheduledJobResponse{}, nil
+	}
+
+	if job.Kind == model.ScheduledJobKindMessage && req.Content != nil {
+		content, err := s.stickers.strip(ctx, *req.Content, req.UserID)
+		if err != nil {
+			s.logger.Error(requestId, "s.stickers.strip", req, err)
+			return nil, s.errWarpper.NewDBServiceError(err)
+		}
+		settings["content"] = content
+	}
+
+	ok, err := s.scheduleRepo.UpdatePendingJob(ctx, req.JobID, settings)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.UpdatePendingJob", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		// the worker claimed it in between
+		return nil, s.errWarpper.NewScheduledJobNotPendingError(req.JobID)
+	}
+	return &dto.UpdateScheduledJobResponse{}, nil
+}
+
+func (s *scheduleServiceImpl) CancelScheduledJob(ctx context.Context, req *dto.CancelScheduledJobRequest) (*dto.CancelScheduledJobResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	_, serviceErr := s.ownPendingJob(ctx, requestId, req.UserID, req.JobID)
+	if serviceErr != nil {
+		return nil, serviceErr
+	}
+
+	ok, err := s.scheduleRepo.UpdatePendingJob(ctx, req.JobID, map[string]interface{}{
+		"status":      model.ScheduledJobStatusCancelled,
+		"finish_time": time.Now(),
+	})
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.UpdatePendingJob", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if !ok {
+		return nil, s.errWarpper.NewScheduledJobNotPendingError(req.JobID)
+	}
+	return &dto.CancelScheduledJobResponse{}, nil
+}
+
+// FetchFiredReminders is where a fired reminder is delivered. It returns the reminders the user did
+// not acknowledge yet with the message they were set on.
+func (s *scheduleServiceImpl) FetchFiredReminders(ctx context.Context, req *dto.FetchFiredRemindersRequest) (*dto.FetchFiredRemindersResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	jobs, err := s.scheduleRepo.FetchFiredReminders(ctx, req.UserID, firedRemindersPageSize)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.FetchFiredReminders", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+
+	answer := dto.FetchFiredRemindersResponse{Reminders: make([]dto.FiredReminder, 0, len(jobs))}
+	for _, job := range jobs {
+		item := dto.FiredReminder{
+			JobID:  job.Id,
+			RoomID: job.RoomID,
+			Note:   job.Content,
+		}
+		if job.FinishedAt != nil {
+			item.FireTime = common.TimeToUint64(*job.FinishedAt)
+		}
+
+		message, err := s.messageRepo.GetMessage(ctx, job.RoomID, job.MessageID)
+		if err != nil {
+			s.logger.Error(requestId, "s.messageRepo.GetMessage", req, err)
+			return nil, s.errWarpper.NewDBServiceError(err)
+		} else if message != nil {
+			item.Message = &dto.Message{
+				ID:        message.ID,
+				UserID:    message.UserID,
+				Kind:      message.Kind,
+				Content:   message.Content,
+				CreatedAt: common.TimeToUint64(message.CreatedAt),
+			}
+		}
+		answer.Reminders = append(answer.Reminders, item)
+	}
+	return &answer, nil
+}
+
+// AckReminders takes fired reminders out of FetchFiredReminders, they stay listed as done jobs.
+func (s *scheduleServiceImpl) AckReminders(ctx context.Context, req *dto.AckRemindersRequest) (*dto.AckRemindersResponse, *dtoError.ServiceError) {
+	requestId := common.GetUUID(ctx)
+	s.logger.Info(requestId, "start", req, nil)
+	defer func() { s.logger.Info(requestId, "end", req, nil) }()
+
+	acked, err := s.scheduleRepo.AckReminders(ctx, req.UserID, req.JobIDs)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.AckReminders", req, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	}
+	return &dto.AckRemindersResponse{Acked: acked}, nil
+}
+
+// ownPendingJob loads a job of the user that can still be changed, jobs of other users are reported
+// as not existing.
+func (s *scheduleServiceImpl) ownPendingJob(ctx context.Context, requestId string, userID uint64, jobID uint64) (*model.ScheduledJob, *dtoError.ServiceError) {
+	job, err := s.scheduleRepo.GetScheduledJob(ctx, jobID)
+	if err != nil {
+		s.logger.Error(requestId, "s.scheduleRepo.GetScheduledJob", jobID, err)
+		return nil, s.errWarpper.NewDBServiceError(err)
+	} else if job == nil || job.UserID != userID {
+		return nil, s.errWarpper.NewScheduledJobNotExistError(jobID)
+	} else if job.Status != model.ScheduledJobStatusPending {
+		return nil, s.errWarpper.NewScheduledJobNotPendingError(jobID)
+	}
+	return job, nil
+}
+
+func validScheduleTime(runAt time.Time) bool {
+	now := time.Now()
+	return runAt.After(now) && runAt.Before(now.Add(maxScheduleAhead))
+}
@@ -230,3 +230,65 @@ func (s *stickerServiceImpl) GetAllAvailableStickersInfo(ctx context.Context, re
 	response.StickerSetInfoList = stickerSetInfoList
 	return &response, nil
 }
+
+type stickerFilter struct {
+	stickerRepo  repository.StickerRepository
+	stickerCache cache.StickerCache
+	logger       logger.Logger
+}
+
+func newStickerFilter() *stickerFilter {
+	return &stickerFilter{
+		stickerRepo:  repository.GetStickerRepository(),
+		stickerCache: cache.GetStickerCache(),
+		logger:       logger.NewLogger(),
+	}
+}
+
+// strip blanks the sticker tokens in content that userID does not own.
+func (s *stickerFilter) strip(ctx context.Context, content string, userID uint64) (string, error) {
+	requestId := common.GetUUID(ctx)
+	data := map[string]any{"userId": userID, "content": content}
+
+	userStickerSetCacheMap, keyExist, err := s.stickerCache.GetAllStickerSetInfoByUser(ctx, userID)
+	if err != nil || !keyExist {
+		s.logger.Error(requestId, "s.stickerCache.GetAllStickerSetInfoByUser", data, err)
+		s.stickerCache.ClearAllStickerCacheByUser(ctx, userID)
+		stickerSetList, err := s.stickerRepo.GetAllAvailableStickersInfo(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+
+		userStickerSetCacheMap = make(map[uint64]*cache.StickerSetCacheInfo)
+		for _, stickerSet := range stickerSetList {
+			stickerSetCacheInfo := &cache.StickerSetCacheInfo{
+				Id:       stickerSet.Id,
+				Name:     stickerSet.Name,
+				Author:   stickerSet.Author,
+				Price:    stickerSet.Price,
+				Stickers: make(map[uint64]*cache.StickerCacheInfo),
+			}
+
+			for _, sticker := range stickerSet.Stickers {
+				stickerSetCacheInfo.Stickers[sticker.Id] = &cache.StickerCacheInfo{
+					Id:   sticker.Id,
+					Name: sticker.Name,
+				}
+			}
+			userStickerSetCacheMap[stickerSet.Id] = stickerSetCacheInfo
+		}
+		err = s.stickerCache.StoreStickerSetInfoByUser(ctx, userID, userStickerSetCacheMap)
+		if err != nil {
+			s.logger.Error(requestId, "s.stickerCache.StoreStickerSetInfoByUser", data, err)
+		}
+	}
+
+	return common.StripStickers(content, func(stickerSetID uint64, stickerID uint64) bool {
+		stickerSet, ok := userStickerSetCacheMap[stickerSetID]
+		if !ok || stickerSet == nil {
+			return false
+		}
+		_, ok = stickerSet.Stickers[stickerID]
+		return ok
+	}), nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// systemEventWriter records membership and settings changes in the room timeline. It has to be
+// called with the transaction context of the change, so the event and the change commit together.
+type systemEventWriter struct {
+	messageRepo repository.MessageRepository
+}
+
+func newSystemEventWriter() *systemEventWriter {
+	return &systemEventWriter{messageRepo: repository.GetMessageRepository()}
+}
+
+func (w *systemEventWriter) write(ctx context.Context, roomID uint64, event dto.SystemEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = w.messageRepo.AddSystemMessage(ctx, roomID, event.ActorUserID, systemEventText(event), string(payload))
+	return err
+}
+
+// systemEventText is the plain text fallback stored as content for clients that do not render events.
+func systemEventText(event dto.SystemEvent) string {
+	switch event.Type {
+	case model.SystemEventMemberJoined:
+		if event.ActorUserID != event.TargetUserID {
+			return fmt.Sprintf("user %d was added by user %d", event.TargetUserID, event.ActorUserID)
+		}
+		return fmt.Sprintf("user %d joined", event.TargetUserID)
+	case model.SystemEventMemberLeft:
+		return fmt.Sprintf("user %d left", event.ActorUserID)
+	case model.SystemEventMemberKicked:
+		return fmt.Sprintf("user %d was removed by user %d", event.TargetUserID, event.ActorUserID)
+	case model.SystemEventMemberBanned:
+		return fmt.Sprintf("user %d was banned by user %d", event.TargetUserID, event.ActorUserID)
+	case model.SystemEventOwnerChanged:
+		return fmt.Sprintf("user %d handed ownership to user %d", event.ActorUserID, event.TargetUserID)
+	case model.SystemEventInvitationAccepted:
+		return fmt.Sprintf("user %d accepted the invitation", event.ActorUserID)
+	case model.SystemEventSettingsUpdated:
+		return fmt.Sprintf("user %d updated room settings: %s", event.ActorUserID, strings.Join(event.Changed, ", "))
+	case model.SystemEventRoomArchived:
+		return fmt.Sprintf("user %d archived the room", event.ActorUserID)
+	case model.SystemEventRoomUnarchived:
+		return fmt.Sprintf("user %d unarchived the room", event.ActorUserID)
+	case model.SystemEventRoomRestored:
+		return fmt.Sprintf("user %d restored the room", event.ActorUserID)
+	case model.SystemEventMessagePinned:
+		return fmt.Sprintf("user %d pinned a message", event.ActorUserID)
+	case model.SystemEventAnnouncementSet:
+		return fmt.Sprintf("user %d updated the announcement", event.ActorUserID)
+	case model.SystemEventMemberWarned:
+		return fmt.Sprintf("user %d was warned by user %d", event.TargetUserID, event.ActorUserID)
+	}
+	return event.Type
+}
+
+// parseSystemEvent decodes the payload of a system message, nil for user messages or bad payloads.
+func parseSystemEvent(message *model.Message) *dto.SystemEvent {
+	if message.Kind != model.MessageKindSystem || message.Payload == "" {
+		return nil
+	}
+	event := dto.SystemEvent{}
+	if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+		return nil
+	}
+	return &event
+}
@@ -86,6 +86,8 @@ func (a *userServiceImpl) UserLoginService(ctx context.Context, req *dto.UserLog
 	err = comparePassword(userModel.Password, req.Password)
 	if err != nil {
 		return nil, a.errWarpper.NewLoginFailedServiceError(err)
+	} else if userModel.SuspendedAt != nil {
+		return nil, a.errWarpper.NewUserSuspendedError(userModel.Id)
 	}
 
 	return &dto.UserLoginResponse{
@@ -0,0 +1,164 @@
+// Package unfurl fetches the Open Graph metadata of links posted in messages. It does not depend on
+// the rest of the application, so it can be exercised against a local server in tests.
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultTimeout      = 5 * time.Second
+	DefaultMaxBodyBytes = 512 << 10
+	maxRedirects        = 3
+	userAgent           = "ChatRoomAPI-LinkPreview/1.0"
+)
+
+var (
+	ErrBlockedAddress = errors.New("unfurl: address is not public")
+	ErrUnsupportedURL = errors.New("unfurl: only http and https urls are fetched")
+	ErrNotHTML        = errors.New("unfurl: response is not html")
+)
+
+// Preview is what a page says about itself, every field may be empty.
+type Preview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	SiteName    string `json:"site_name"`
+}
+
+// Empty reports whether the page gave nothing worth showing.
+func (p *Preview) Empty() bool {
+	return p.Title == "" && p.Description == "" && p.ImageURL == ""
+}
+
+// Fetcher downloads pages for previews. Every connection, including the ones made for redirects,
+// is checked after DNS resolution, so a name pointing at a private address is refused as well.
+type Fetcher struct {
+	client       *http.Client
+	maxBodyBytes int64
+}
+
+// NewFetcher returns a fetcher that only connects to public addresses.
+func NewFetcher(timeout time.Duration, maxBodyBytes int64) *Fetcher {
+	return newFetcher(timeout, maxBodyBytes, func(addr netip.AddrPort) bool { return isPublicAddr(addr.Addr()) })
+}
+
+// newFetcher takes the dial check as a parameter so tests can let it reach a local server.
+func newFetcher(timeout time.Duration, maxBodyBytes int64, allow func(netip.AddrPort) bool) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allow(netip.AddrPortFrom(addr.Addr().Unmap(), addr.Port())) {
+				return ErrBlockedAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		// no proxy from the environment, the address check has to see the real destination
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("unfurl: stopped after %d redirects", maxRedirects)
+			}
+			return checkScheme(req.URL)
+		},
+	}
+	return &Fetcher{client: client, maxBodyBytes: maxBodyBytes}
+}
+
+// Fetch downloads at most the configured number of bytes of rawURL and reads its metadata.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	} else if err := checkScheme(target); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unfurl: unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, ErrNotHTML
+	}
+
+	preview := parse(io.LimitReader(resp.Body, f.maxBodyBytes), resp.Request.URL)
+	preview.URL = rawURL
+	return preview, nil
+}
+
+func checkScheme(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrUnsupportedURL
+	}
+	return nil
+}
+
+// reservedPrefixes are ranges netip does not classify but that are not reachable on the internet
+// either.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package unfurl
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const maxFieldLength = 300
+
+// parse reads the head of a page, Open Graph tags win over the title element and the description
+// meta tag. It stops at the body, metadata is not expected there.
+func parse(r io.Reader, base *url.URL) *Preview {
+	preview := &Preview{}
+	var title, description string
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finish(preview, title, description, base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				return finish(preview, title, description, base)
+			case "title":
+				inTitle = true
+			case "meta":
+				key, content := metaAttr(token, "property"), metaAttr(token, "content")
+				if key == "" {
+					key = metaAttr(token, "name")
+				}
+				switch strings.ToLower(key) {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image", "og:image:url":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "og:site_name":
+					preview.SiteName = content
+				case "description":
+					description = content
+				}
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			if tokenizer.Token().Data == "title" {
+				inTitle = false
+			}
+		}
+	}
+}
+
+func finish(preview *Preview, title string, description string, base *url.URL) *Preview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	preview.Title = clean(preview.Title)
+	preview.Description = clean(preview.Description)
+	preview.SiteName = clean(preview.SiteName)
+	preview.ImageURL = resolveImage(preview.ImageURL, base)
+	return preview
+}
+
+// resolveImage makes a relative image url absolute and drops anything that is not http or https.
+func resolveImage(raw string, base *url.URL) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	image, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		image = base.ResolveReference(image)
+	}
+	if checkScheme(image) != nil {
+		return ""
+	}
+	return image.String()
+}
+
+func metaAttr(token html.Token, name string) string {
+	for _, attr := range token.Attr {
+		if strings.EqualFold(attr.Key, name) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+var spaces = regexp.MustCompile(`\s+`)
+
+func clean(s string) string {
+	s = strings.TrimSpace(spaces.ReplaceAllString(s, " "))
+	if runes := []rune(s); len(runes) > maxFieldLength {
+		s = string(runes[:maxFieldLength])
+	}
+	return s
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// ExtractURLs returns the distinct http and https links of content in order, at most max of them.
+func ExtractURLs(content string, max int) []string {
+	seen := map[string]struct{}{}
+	urls := []string{}
+	for _, match := range urlPattern.FindAllString(content, -1) {
+		match = strings.TrimRight(match, ".,;:!?)]}'")
+		if _, ok := seen[match]; ok {
+			continue
+		}
+		if u, err := url.Parse(match); err != nil || checkScheme(u) != nil {
+			continue
+		}
+		seen[match] = struct{}{}
+		urls = append(urls, match)
+		if len(urls) == max {
+			break
+		}
+	}
+	return urls
+}
@@ -0,0 +1,213 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves the given handlers, the fetchers below are allowed to reach it on loopback.
+func newTestServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	for path, handler := range routes {
+		mux.HandleFunc(path, handler)
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func allowAll(netip.AddrPort) bool { return true }
+
+func servePage(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body))
+	}
+}
+
+func TestFetchOpenGraph(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/article": servePage(`<html><head>
+			<title>Plain title</title>
+			<meta property="og:title" content="  The   OG title ">
+			<meta property="og:description" content="What it is about">
+			<meta property="og:image" content="/img/cover.png">
+			<meta property="og:site_name" content="Example">
+			</head><body><meta property="og:title" content="ignored"></body></html>`),
+	})
+
+	fetcher := newFetcher(time.Second, DefaultMaxBodyBytes, allowAll)
+	preview, err := fetcher.Fetch(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	want := Preview{
+		URL:         server.URL + "/article",
+		Title:       "The OG title",
+		Description: "What it is about",
+		ImageURL:    server.URL + "/img/cover.png",
+		SiteName:    "Example",
+	}
+	if *preview != want {
+		t.Fatalf("got %+v, want %+v", *preview, want)
+	}
+}
+
+func TestFetchFallsBackToTitleAndDescription(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/": servePage(`<html><head><title>Only a title</title><meta name="description" content="Meta description"></head></html>`),
+	})
+
+	preview, err := newFetcher(time.Second, DefaultMaxBodyBytes, allowAll).Fetch(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if preview.Title != "Only a title" || preview.Description != "Meta description" || preview.ImageURL != "" {
+		t.Fatalf("unexpected preview %+v", *preview)
+	}
+}
+
+func TestFetchFollowsRedirects(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/short": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/long", http.StatusFound)
+		},
+		"/long": servePage(`<head><title>Landed</title></head>`),
+	})
+
+	preview, err := newFetcher(time.Second, DefaultMaxBodyBytes, allowAll).Fetch(context.Background(), server.URL+"/short")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if preview.Title != "Landed" || preview.URL != server.URL+"/short" {
+		t.Fatalf("unexpected preview %+v", *preview)
+	}
+}
+
+func TestFetchStopsAtBodyLimit(t *testing.T) {
+	padding := strings.Repeat("<!-- padding -->", 1000)
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/": servePage(`<head><title>Early</title>` + padding + `<meta property="og:description" content="too late"></head>`),
+	})
+
+	preview, err := newFetcher(time.Second, 1024, allowAll).Fetch(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if preview.Title != "Early" || preview.Description != "" {
+		t.Fatalf("read past the body limit: %+v", *preview)
+	}
+}
+
+func TestFetchTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/slow": func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		},
+	})
+	defer close(release)
+
+	start := time.Now()
+	_, err := newFetcher(100*time.Millisecond, DefaultMaxBodyBytes, allowAll).Fetch(context.Background(), server.URL+"/slow")
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("timeout took %v", elapsed)
+	}
+}
+
+func TestFetchRejectsNonHTML(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/file": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("<title>not a page</title>"))
+		},
+	})
+
+	_, err := newFetcher(time.Second, DefaultMaxBodyBytes, allowAll).Fetch(context.Background(), server.URL+"/file")
+	if !errors.Is(err, ErrNotHTML) {
+		t.Fatalf("got %v, want ErrNotHTML", err)
+	}
+}
+
+func TestFetchBlocksPrivateAddresses(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/": servePage(`<head><title>internal</title></head>`),
+	})
+
+	_, err := NewFetcher(time.Second, DefaultMaxBodyBytes).Fetch(context.Background(), server.URL+"/")
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("got %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestFetchBlocksRedirectToPrivateAddress(t *testing.T) {
+	internal := newTestServer(t, map[string]http.HandlerFunc{
+		"/": servePage(`<head><title>internal</title></head>`),
+	})
+	public := newTestServer(t, map[string]http.HandlerFunc{
+		"/": func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, internal.URL+"/", http.StatusFound)
+		},
+	})
+	// both servers listen on loopback, so only the port tells the allowed one apart
+	publicAddr := netip.MustParseAddrPort(strings.TrimPrefix(public.URL, "http://"))
+	fetcher := newFetcher(time.Second, DefaultMaxBodyBytes, func(addr netip.AddrPort) bool {
+		return addr == publicAddr
+	})
+
+	_, err := fetcher.Fetch(context.Background(), public.URL+"/")
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("got %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestFetchRejectsOtherSchemes(t *testing.T) {
+	_, err := NewFetcher(time.Second, DefaultMaxBodyBytes).Fetch(context.Background(), "file:///etc/passwd")
+	if !errors.Is(err, ErrUnsupportedURL) {
+		t.Fatalf("got %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+	}
+	for raw, want := range cases {
+		if got := isPublicAddr(netip.MustParseAddr(raw)); got != want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	content := "see https://example.com/a, and (http://example.org/b) again https://example.com/a ftp://x.y https://c.example/"
+	got := ExtractURLs(content, 2)
+	want := []string{"https://example.com/a", "http://example.org/b"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"ChatRoomAPI/src/importer"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/repository"
+	"context"
+)
+
+// chatImport runs the imports uploaded through the import endpoint, one per run since an archive
+// can be large.
+type chatImport struct {
+	importRepo repository.ImportRepository
+	importer   *importer.Importer
+	logger     logger.Logger
+}
+
+func init() {
+	Register(&chatImport{
+		importRepo: repository.GetImportRepository(),
+		importer:   importer.New(),
+		logger:     logger.NewLogger(),
+	})
+}
+
+func (c *chatImport) Name() string {
+	return "chat_import"
+}
+
+func (c *chatImport) Run(ctx context.Context) error {
+	txContext, tx := repository.SetTxContext(ctx)
+	job, err := c.importRepo.ClaimPendingImport(txContext)
+	if err != nil {
+		tx.Rollback()
+		return err
+	} else if job == nil {
+		tx.Rollback()
+		return nil
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	result, err := c.importer.Run(ctx, job.Source, job.Archive, job.RequestedByUserID)
+	if err != nil {
+		c.logger.Error(c.Name(), "c.importer.Run", job.Id, err)
+		if err := c.importRepo.FailImport(ctx, job.Id, err.Error()); err != nil {
+			c.logger.Error(c.Name(), "c.importRepo.FailImport", job.Id, err)
+		}
+		return nil
+	}
+
+	if err := c.importRepo.FinishImport(ctx, job.Id, result.RoomCount, result.MessageCount); err != nil {
+		return err
+	}
+	c.logger.Info(c.Name(), "done", map[string]any{"import_id": job.Id, "rooms": result.RoomCount, "messages": result.MessageCount}, nil)
+	return nil
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+const (
+	messageRetentionBatchSize = 500
+	// messageRetentionMaxBatch caps the work of one run per room, the rest waits for the next run.
+	messageRetentionMaxBatch = 20
+)
+
+// messageRetention deletes messages older than the retention of their room. It deletes in small
+// batches outside of a transaction, so messages is never locked for long.
+type messageRetention struct {
+	roomRepo    repository.RoomRepository
+	messageRepo repository.MessageRepository
+	logger      logger.Logger
+}
+
+func init() {
+	Register(&messageRetention{
+		roomRepo:    repository.GetRoomRepository(),
+		messageRepo: repository.GetMessageRepository(),
+		logger:      logger.NewLogger(),
+	})
+}
+
+func (m *messageRetention) Name() string {
+	return "message_retention"
+}
+
+func (m *messageRetention) Run(ctx context.Context) error {
+	rooms, err := m.roomRepo.FetchRetentionRooms(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deleted int64
+	for _, room := range rooms {
+		before := time.Now().Add(-time.Duration(room.RetentionDay) * 24 * time.Hour)
+		for batch := 0; batch < messageRetentionMaxBatch; batch++ {
+			count, err := m.messageRepo.DeleteMessagesBefore(ctx, room.Id, before, messageRetentionBatchSize)
+			if err != nil {
+				m.logger.Error(m.Name(), "m.messageRepo.DeleteMessagesBefore", room.Id, err)
+				break
+			}
+			deleted += count
+			if count < messageRetentionBatchSize {
+				break
+			}
+		}
+	}
+	m.logger.Info(m.Name(), "done", map[string]int64{"rooms": int64(len(rooms)), "messages": deleted}, nil)
+	return nil
+}
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+// moderationCleanup deletes expired room bans and clears expired mutes. Both are already ignored
+// once expired, the job only keeps the tables small.
+type moderationCleanup struct {
+	roomRepo    repository.RoomRepository
+	roomBanRepo repository.RoomBanRepository
+	logger      logger.Logger
+}
+
+func init() {
+	Register(&moderationCleanup{
+		roomRepo:    repository.GetRoomRepository(),
+		roomBanRepo: repository.GetRoomBanRepository(),
+		logger:      logger.NewLogger(),
+	})
+}
+
+func (m *moderationCleanup) Name() string {
+	return "moderation_cleanup"
+}
+
+func (m *moderationCleanup) Run(ctx context.Context) error {
+	now := time.Now()
+	bans, err := m.roomBanRepo.DeleteExpiredBans(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	mutes, err := m.roomRepo.ClearExpiredMutes(ctx, now)
+	if err != nil {
+		return err
+	}
+	m.logger.Info(m.Name(), "done", map[string]int64{"bans": bans, "mutes": mutes}, nil)
+	return nil
+}
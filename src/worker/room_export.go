@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/export"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+const (
+	// roomExportMaxJob caps the exports built in one run.
+	roomExportMaxJob        = 5
+	defaultExportExpireHour = 24
+)
+
+// roomExport builds the transcripts requested through the export endpoint and drops the ones
+// whose download link expired.
+type roomExport struct {
+	exportRepo repository.RoomExportRepository
+	builder    *export.Builder
+	logger     logger.Logger
+}
+
+func init() {
+	Register(&roomExport{
+		exportRepo: repository.GetRoomExportRepository(),
+		builder:    export.NewBuilder(),
+		logger:     logger.NewLogger(),
+	})
+}
+
+func (r *roomExport) Name() string {
+	return "room_export"
+}
+
+func (r *roomExport) Run(ctx context.Context) error {
+	expired, err := r.exportRepo.DeleteExpiredExports(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	built := 0
+	for built < roomExportMaxJob {
+		txContext, tx := repository.SetTxContext(ctx)
+		job, err := r.exportRepo.ClaimPendingExport(txContext)
+		if err != nil {
+			tx.Rollback()
+			return err
+		} else if job == nil {
+			tx.Rollback()
+			break
+		}
+		if err := tx.Commit().Error; err != nil {
+			return err
+		}
+
+		if err := r.build(ctx, job); err != nil {
+			r.logger.Error(r.Name(), "r.build", job.Id, err)
+			if err := r.exportRepo.FailExport(ctx, job.Id, err.Error()); err != nil {
+				r.logger.Error(r.Name(), "r.exportRepo.FailExport", job.Id, err)
+			}
+		}
+		built++
+	}
+	r.logger.Info(r.Name(), "done", map[string]int64{"built": int64(built), "expired": expired}, nil)
+	return nil
+}
+
+func (r *roomExport) build(ctx context.Context, job *model.RoomExport) error {
+	transcript, err := r.builder.Build(ctx, job.RoomID)
+	if err != nil {
+		return err
+	}
+	content, err := export.Render(job.Format, transcript)
+	if err != nil {
+		return err
+	}
+	return r.exportRepo.FinishExport(ctx, job.Id, content, len(transcript.Messages), time.Now().Add(exportExpire()))
+}
+
+func exportExpire() time.Duration {
+	hour := src.GlobalConfig.YamlConfig.Export.ExpireHour
+	if hour <= 0 {
+		hour = defaultExportExpireHour
+	}
+	return time.Duration(hour) * time.Hour
+}
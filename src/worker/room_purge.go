@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/repository"
+	"context"
+	"time"
+)
+
+const roomPurgeBatchSize = 50
+
+// roomPurge hard deletes rooms whose restore window has passed, together with every dependent row.
+// Each room is purged in its own transaction so one failure does not hold back the rest.
+type roomPurge struct {
+	roomRepo repository.RoomRepository
+	logger   logger.Logger
+}
+
+func init() {
+	Register(&roomPurge{
+		roomRepo: repository.GetRoomRepository(),
+		logger:   logger.NewLogger(),
+	})
+}
+
+func (p *roomPurge) Name() string {
+	return "room_purge"
+}
+
+func (p *roomPurge) Run(ctx context.Context) error {
+	deletedBefore := time.Now().Add(-repository.DeletedRoomRetention())
+	roomIDs, err := p.roomRepo.FetchPurgeableRoomIDs(ctx, deletedBefore, roomPurgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	purged := 0
+	for _, roomID := range roomIDs {
+		txContext, tx := repository.SetTxContext(ctx)
+		if err := p.roomRepo.PurgeRoom(txContext, roomID); err != nil {
+			tx.Rollback()
+			p.logger.Error(p.Name(), "p.roomRepo.PurgeRoom", roomID, err)
+			continue
+		}
+		if err := tx.Commit().Error; err != nil {
+			p.logger.Error(p.Name(), "tx.Commit", roomID, err)
+			continue
+		}
+		purged++
+	}
+	p.logger.Info(p.Name(), "done", map[string]int{"purged": purged}, nil)
+	return nil
+}
@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"ChatRoomAPI/src/dto"
+	"ChatRoomAPI/src/logger"
+	"ChatRoomAPI/src/model"
+	"ChatRoomAPI/src/repository"
+	"ChatRoomAPI/src/service"
+	"context"
+	"errors"
+	"time"
+)
+
+// scheduledJobBatch caps the jobs claimed in one run.
+const scheduledJobBatch = 50
+
+// scheduledJob posts the scheduled messages and fires the reminders that are due.
+type scheduledJob struct {
+	scheduleRepo repository.ScheduledJobRepository
+	roomRepo     repository.RoomRepository
+	messageRepo  repository.MessageRepository
+	logger       logger.Logger
+}
+
+func init() {
+	Register(&scheduledJob{
+		scheduleRepo: repository.GetScheduledJobRepository(),
+		roomRepo:     repository.GetRoomRepository(),
+		messageRepo:  repository.GetMessageRepository(),
+		logger:       logger.NewLogger(),
+	})
+}
+
+func (s *scheduledJob) Name() string {
+	return "scheduled_job"
+}
+
+func (s *scheduledJob) Run(ctx context.Context) error {
+	txContext, tx := repository.SetTxContext(ctx)
+	jobs, err := s.scheduleRepo.ClaimDueJobs(txContext, time.Now(), scheduledJobBatch)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	done, failed := 0, 0
+	for _, job := range jobs {
+		if err := s.execute(ctx, job); err != nil {
+			s.logger.Error(s.Name(), "s.execute", job.Id, err)
+			if err := s.scheduleRepo.FailScheduledJob(ctx, job.Id, err.Error()); err != nil {
+				s.logger.Error(s.Name(), "s.scheduleRepo.FailScheduledJob", job.Id, err)
+			}
+			failed++
+			continue
+		}
+		if err := s.scheduleRepo.FinishScheduledJob(ctx, job.Id); err != nil {
+			s.logger.Error(s.Name(), "s.scheduleRepo.FinishScheduledJob", job.Id, err)
+		}
+		done++
+	}
+	s.logger.Info(s.Name(), "done", map[string]int{"done": done, "failed": failed}, nil)
+	return nil
+}
+
+// execute posts a message through the message service, so it is checked like one sent by hand. A
+// reminder only needs the user to still see the message, finishing it delivers it to the user's
+// fired reminders. Suspending a user cancels their pending jobs, the status check covers the ones
+// already claimed.
+func (s *scheduledJob) execute(ctx context.Context, job *model.ScheduledJob) error {
+	if job.Kind == model.ScheduledJobKindMessage {
+		status, serviceErr := service.GetOpsService().AccountStatus(ctx, job.UserID)
+		if serviceErr != nil {
+			return errors.New(serviceErr.ExtrenalReason)
+		} else if status.Suspended {
+			return errors.New("user is suspended")
+		}
+
+		_, serviceErr = service.GetMessageService().AddMessage(ctx, &dto.AddMessageRequest{
+			RoomID:  job.RoomID,
+			UserID:  job.UserID,
+			Content: job.Content,
+		})
+		if serviceErr != nil {
+			return errors.New(serviceErr.ExtrenalReason)
+		}
+		return nil
+	}
+
+	inRoom, err := s.roomRepo.CheckUserInRoom(ctx, job.RoomID, job.UserID)
+	if err != nil {
+		return err
+	} else if !inRoom {
+		return errors.New("user is no longer in the room")
+	}
+	message, err := s.messageRepo.GetMessage(ctx, job.RoomID, job.MessageID)
+	if err != nil {
+		return err
+	} else if message == nil {
+		return errors.New("message was deleted")
+	}
+	return nil
+}
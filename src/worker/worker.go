@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"ChatRoomAPI/src"
+	"ChatRoomAPI/src/logger"
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Job is a periodic background task. Run is called once per interval and should finish well within it.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+const defaultInterval = time.Minute
+
+var jobs []Job
+
+// Register adds a job to be started by Start, it is meant to be called from init functions.
+func Register(job Job) {
+	jobs = append(jobs, job)
+}
+
+// interval reads worker.interval_second.<job name> from config and falls back to
+// worker.default_interval_second and then to one minute.
+func interval(name string) time.Duration {
+	w := src.GlobalConfig.YamlConfig.Worker
+	if second, ok := w.IntervalSecond[name]; ok && second > 0 {
+		return time.Duration(second) * time.Second
+	} else if w.DefaultIntervalSecond > 0 {
+		return time.Duration(w.DefaultIntervalSecond) * time.Second
+	}
+	return defaultInterval
+}
+
+// Start runs every registered job on its own ticker until ctx is cancelled.
+func Start(ctx context.Context) {
+	log := logger.NewLogger()
+	for _, job := range jobs {
+		go run(ctx, job, interval(job.Name()), log)
+	}
+}
+
+func run(ctx context.Context, job Job, every time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runOnce(ctx, job, log)
+		}
+	}
+}
+
+// runOnce keeps a panicking job from taking the whole process down, the job is retried on the next tick.
+func runOnce(ctx context.Context, job Job, log logger.Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error(job.Name(), "job.Run", nil, fmt.Errorf("panic: %v\n%s", r, debug.Stack()))
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Error(job.Name(), "job.Run", nil, err)
+	}
+}